@@ -0,0 +1,121 @@
+package scrapeapi
+
+import (
+	"fmt"
+	"time"
+)
+
+// ActionType is the kind of browser interaction an Action performs
+type ActionType string
+
+// Browser actions supported by the headless browser
+const (
+	ActionClick  ActionType = "click"
+	ActionFill   ActionType = "fill"
+	ActionScroll ActionType = "scroll"
+	ActionWait   ActionType = "wait"
+	ActionPress  ActionType = "press"
+)
+
+// Action is a browser interaction run on the target page before extraction,
+// e.g. clicking a "load more" button. Build actions with Click, Fill, Scroll,
+// WaitForSelector, WaitForDuration and Press.
+type Action struct {
+	Type     ActionType `json:"type"`
+	Selector string     `json:"selector,omitempty"`
+	Text     string     `json:"text,omitempty"`
+	Key      string     `json:"key,omitempty"`
+	// Times is how many screen heights to scroll
+	Times int `json:"times,omitempty"`
+	// DurationMS is how long to wait, in milliseconds
+	DurationMS int `json:"duration_ms,omitempty"`
+}
+
+// Click clicks the first element matching a CSS selector
+func Click(selector string) Action {
+	return Action{Type: ActionClick, Selector: selector}
+}
+
+// Fill types text into the input matching a CSS selector, replacing its value
+func Fill(selector, text string) Action {
+	return Action{Type: ActionFill, Selector: selector, Text: text}
+}
+
+// Scroll scrolls down n screen heights, e.g. to trigger infinite scrolling
+func Scroll(n int) Action {
+	return Action{Type: ActionScroll, Times: n}
+}
+
+// WaitForSelector waits until an element matching a CSS selector appears
+func WaitForSelector(selector string) Action {
+	return Action{Type: ActionWait, Selector: selector}
+}
+
+// WaitForDuration pauses for d
+func WaitForDuration(d time.Duration) Action {
+	return Action{Type: ActionWait, DurationMS: int(d / time.Millisecond)}
+}
+
+// Press presses a keyboard key, e.g. "Enter" or "PageDown"
+func Press(key string) Action {
+	return Action{Type: ActionPress, Key: key}
+}
+
+func (a Action) validate(v *validator, field string) {
+	switch a.Type {
+	case ActionClick, ActionFill:
+		if a.Selector == "" {
+			v.addf(field+".selector", "is required for %s", a.Type)
+		}
+	case ActionScroll:
+		if a.Times <= 0 {
+			v.addf(field+".times", "must be positive, got %d", a.Times)
+		}
+	case ActionWait:
+		if (a.Selector == "") == (a.DurationMS <= 0) {
+			v.addf(field, "wait needs either a selector or a positive duration")
+		}
+	case ActionPress:
+		if a.Key == "" {
+			v.addf(field+".key", "is required for press")
+		}
+	default:
+		v.addf(field+".type", "unknown action %q", a.Type)
+	}
+}
+
+// validateActions checks a list of actions stored under field
+func validateActions(v *validator, field string, actions []Action) {
+	for i, a := range actions {
+		a.validate(v, fmt.Sprintf("%s[%d]", field, i))
+	}
+}
+
+// FormConfig fills in and submits a form on the target page before
+// extraction, e.g. a search box or a set of filters
+type FormConfig struct {
+	// Fields maps CSS selectors of form inputs to the values typed into them.
+	// Fields are filled in no particular order; use Actions when order matters.
+	Fields map[string]string `json:"fields"`
+	// SubmitSelector is the CSS selector of the button that submits the form
+	SubmitSelector string `json:"submit_selector"`
+	// WaitFor holds off extraction until the results of the submission are ready
+	WaitFor *WaitCondition `json:"wait_for,omitempty"`
+}
+
+func (f *FormConfig) validate(v *validator, field string) {
+	if len(f.Fields) == 0 {
+		v.addf(field+".fields", "needs at least one field")
+	}
+	for sel := range f.Fields {
+		if sel == "" {
+			v.addf(field+".fields", "selector must not be empty")
+		}
+	}
+	if f.SubmitSelector == "" {
+		v.addf(field+".submit_selector", "is required")
+	}
+	if f.WaitFor != nil {
+		f.WaitFor.validate(v, field+".wait_for")
+	}
+}
@@ -0,0 +1,140 @@
+package scrapeapi
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+)
+
+// ErrNoArtifact is returned when a job has no artifact of the requested type
+var ErrNoArtifact = errors.New("job has no such artifact")
+
+// ArtifactType is the kind of file the server captured during a job
+type ArtifactType string
+
+// Artifact types captured by the API
+const (
+	ArtifactScreenshot ArtifactType = "screenshot"
+	ArtifactHTML       ArtifactType = "html"
+	ArtifactMarkdown   ArtifactType = "markdown"
+	ArtifactPDF        ArtifactType = "pdf"
+)
+
+// GetScrapeArtifacts lists the files captured during a job
+func (c *Client) GetScrapeArtifacts(ctx context.Context, requestID string) ([]Artifact, error) {
+	// Create a span for this operation
+	// If there's no existing span in context, this creates a new root span
+	ctx, span := c.tracer.Start(ctx, "scrapeapi.GetScrapeArtifacts")
+	defer span.End()
+
+	var resp struct {
+		Artifacts []Artifact `json:"artifacts"`
+	}
+	if err := c.do(ctx, http.MethodGet, "/v1/scrape/"+url.PathEscape(requestID)+"/artifacts", nil, &resp); err != nil {
+		return nil, err
+	}
+
+	return resp.Artifacts, nil
+}
+
+// Artifact returns the first artifact of type typ listed in the response
+func (r *ScrapeResponse) Artifact(typ ArtifactType) (Artifact, bool) {
+	for _, a := range r.Artifacts {
+		if a.Type == typ {
+			return a, true
+		}
+	}
+	return Artifact{}, false
+}
+
+// SaveScreenshot downloads the screenshot captured for a job requested with
+// CaptureScreenshot and writes it to path. It returns ErrNoArtifact if the
+// job has no screenshot.
+func (c *Client) SaveScreenshot(ctx context.Context, requestID, path string) error {
+	// Create a span for this operation
+	// If there's no existing span in context, this creates a new root span
+	ctx, span := c.tracer.Start(ctx, "scrapeapi.SaveScreenshot")
+	defer span.End()
+
+	artifacts, err := c.GetScrapeArtifacts(ctx, requestID)
+	if err != nil {
+		return err
+	}
+	resp := ScrapeResponse{RequestID: requestID, Artifacts: artifacts}
+	shot, ok := resp.Artifact(ArtifactScreenshot)
+	if !ok {
+		return fmt.Errorf("screenshot of %s: %w", requestID, ErrNoArtifact)
+	}
+
+	return c.saveArtifact(ctx, shot.ID, path)
+}
+
+// DownloadPDF writes the PDF rendering of a job requested with CapturePDF to w.
+// It returns ErrNoArtifact if the job has no PDF.
+func (c *Client) DownloadPDF(ctx context.Context, requestID string, w io.Writer) error {
+	// Create a span for this operation
+	// If there's no existing span in context, this creates a new root span
+	ctx, span := c.tracer.Start(ctx, "scrapeapi.DownloadPDF")
+	defer span.End()
+
+	artifacts, err := c.GetScrapeArtifacts(ctx, requestID)
+	if err != nil {
+		return err
+	}
+	resp := ScrapeResponse{RequestID: requestID, Artifacts: artifacts}
+	pdf, ok := resp.Artifact(ArtifactPDF)
+	if !ok {
+		return fmt.Errorf("pdf of %s: %w", requestID, ErrNoArtifact)
+	}
+
+	return c.DownloadArtifact(ctx, pdf.ID, w)
+}
+
+// saveArtifact downloads an artifact to path, removing the file if the download fails
+func (c *Client) saveArtifact(ctx context.Context, artifactID, path string) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	if err := c.DownloadArtifact(ctx, artifactID, f); err != nil {
+		f.Close()
+		os.Remove(path)
+		return err
+	}
+	return f.Close()
+}
+
+// DownloadArtifact writes the content of an artifact to w.
+// The download is not retried, since w cannot be rewound.
+func (c *Client) DownloadArtifact(ctx context.Context, artifactID string, w io.Writer) error {
+	// Create a span for this operation
+	// If there's no existing span in context, this creates a new root span
+	ctx, span := c.tracer.Start(ctx, "scrapeapi.DownloadArtifact")
+	defer span.End()
+
+	return c.download(ctx, "/v1/artifacts/"+url.PathEscape(artifactID), w)
+}
+
+// download streams the body of a GET request to w
+func (c *Client) download(ctx context.Context, path string, w io.Writer) error {
+	httpReq, err := c.newRequest(ctx, http.MethodGet, path, nil)
+	if err != nil {
+		return err
+	}
+
+	resp, err := c.send(c.longLivedClient(), httpReq)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if _, err := io.Copy(w, resp.Body); err != nil {
+		return fmt.Errorf("download: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,65 @@
+package scrapeapi
+
+// SiteAuthConfig authenticates the job against the target website, so pages
+// behind a login can be scraped. Set exactly one of Basic, BearerToken and Login.
+type SiteAuthConfig struct {
+	// Basic sends HTTP basic auth credentials with every target request
+	Basic *BasicAuth `json:"basic,omitempty"`
+	// BearerToken is sent as "Authorization: Bearer <token>" with every target request
+	BearerToken string `json:"bearer_token,omitempty"`
+	// Login fills in and submits a login form before visiting the target page
+	Login *LoginFlow `json:"login,omitempty"`
+}
+
+// BasicAuth holds HTTP basic auth credentials
+type BasicAuth struct {
+	Username string `json:"username"`
+	Password string `json:"password"`
+}
+
+// LoginFlow is a scripted login through the target site's login form
+type LoginFlow struct {
+	URL              string `json:"url"`
+	Username         string `json:"username"`
+	Password         string `json:"password"`
+	UsernameSelector string `json:"username_selector"`
+	PasswordSelector string `json:"password_selector"`
+	SubmitSelector   string `json:"submit_selector"`
+	// Success tells when the login went through, e.g. a selector only shown to signed-in users.
+	// Without it the job continues once the form is submitted.
+	Success *WaitCondition `json:"success,omitempty"`
+}
+
+func (a *SiteAuthConfig) validate(v *validator, field string) {
+	set := 0
+	for _, ok := range []bool{a.Basic != nil, a.BearerToken != "", a.Login != nil} {
+		if ok {
+			set++
+		}
+	}
+	switch {
+	case set == 0:
+		v.addf(field, "needs one of basic, bearer_token or login")
+	case set > 1:
+		v.addf(field, "basic, bearer_token and login are mutually exclusive")
+	}
+
+	if a.Basic != nil && a.Basic.Username == "" {
+		v.addf(field+".basic.username", "is required")
+	}
+	if l := a.Login; l != nil {
+		v.checkURL(field+".login.url", l.URL)
+		if l.UsernameSelector == "" {
+			v.addf(field+".login.username_selector", "is required")
+		}
+		if l.PasswordSelector == "" {
+			v.addf(field+".login.password_selector", "is required")
+		}
+		if l.SubmitSelector == "" {
+			v.addf(field+".login.submit_selector", "is required")
+		}
+		if l.Success != nil {
+			l.Success.validate(v, field+".login.success")
+		}
+	}
+}
@@ -0,0 +1,144 @@
+package scrapeapi
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sync"
+)
+
+// maxBatchWaiters caps how many jobs of a batch WaitForBatch polls concurrently
+const maxBatchWaiters = 8
+
+// BatchScrapeRequest submits the same extraction for many URLs.
+// The embedded ScrapeRequest holds the prompt, schema, LLM config and other
+// settings shared by every URL; its WebsiteURL, WebsiteHTML, Sources and
+// SearchQuery must be left unset, and Validate reports them otherwise.
+type BatchScrapeRequest struct {
+	URLs []string `json:"urls"`
+	ScrapeRequest
+}
+
+// BatchItem is the outcome of one URL of a batch
+type BatchItem struct {
+	URL       string
+	RequestID string
+	// Response is the last known state of the job; nil if it never started
+	Response *ScrapeResponse
+	// Err is set if the job could not be started, failed, or could not be waited for
+	Err error
+}
+
+// BatchResult aggregates the outcomes of a batch, in the order of the submitted URLs
+type BatchResult struct {
+	Items []BatchItem
+}
+
+// Succeeded returns the items that completed successfully
+func (r *BatchResult) Succeeded() []BatchItem {
+	var items []BatchItem
+	for _, item := range r.Items {
+		if item.Err == nil {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// Failed returns the items that did not complete successfully
+func (r *BatchResult) Failed() []BatchItem {
+	var items []BatchItem
+	for _, item := range r.Items {
+		if item.Err != nil {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// StartBatchScrape starts one job per URL sharing the same prompt, schema and LLM config
+func (c *Client) StartBatchScrape(ctx context.Context, req *BatchScrapeRequest, opts ...RequestOption) (*BatchScrapeResponse, error) {
+	// Create a span for this operation
+	// If there's no existing span in context, this creates a new root span
+	ctx, span := c.tracer.Start(ctx, "scrapeapi.StartBatchScrape")
+	defer span.End()
+
+	shared, err := c.resolveLLM(&req.ScrapeRequest)
+	if err != nil {
+		return nil, err
+	}
+	if shared != &req.ScrapeRequest {
+		req = &BatchScrapeRequest{URLs: req.URLs, ScrapeRequest: *shared}
+	}
+	span.SetAttributes(attrGraph.String(string(req.Graph)), attrBatchSize.Int(len(req.URLs)))
+	if req.LLM != nil && req.LLM.Model != "" {
+		span.SetAttributes(attrModel.String(req.LLM.Model))
+	}
+	if err := req.Validate(); err != nil {
+		return nil, err
+	}
+	if err := c.checkRobots(ctx, &req.ScrapeRequest, req.URLs...); err != nil {
+		return nil, err
+	}
+	if traced := c.withTraceContext(ctx, &req.ScrapeRequest); traced != &req.ScrapeRequest {
+		req = &BatchScrapeRequest{URLs: req.URLs, ScrapeRequest: *traced}
+	}
+	if c.retryPolicy.MaxAttempts > 1 {
+		opts = withIdempotencyKey(opts)
+	}
+
+	var batchResp BatchScrapeResponse
+	if err := c.do(ctx, http.MethodPost, "/v1/scrape/batch", req, &batchResp, opts...); err != nil {
+		return nil, err
+	}
+
+	return &batchResp, nil
+}
+
+// WaitForBatch waits for every job of a batch and collects per-URL results and errors.
+// The returned error is only set if ctx ended before all jobs finished; individual
+// job failures are reported on the items.
+func (c *Client) WaitForBatch(ctx context.Context, batch *BatchScrapeResponse, opts ...WaitOption) (*BatchResult, error) {
+	// Create a span for this operation
+	// If there's no existing span in context, this creates a new root span
+	ctx, span := c.tracer.Start(ctx, "scrapeapi.WaitForBatch")
+	defer span.End()
+
+	result := &BatchResult{Items: make([]BatchItem, len(batch.Jobs))}
+	sem := make(chan struct{}, maxBatchWaiters)
+	var wg sync.WaitGroup
+
+	for i, job := range batch.Jobs {
+		item := &result.Items[i]
+		item.URL = job.URL
+		item.RequestID = job.RequestID
+
+		if job.RequestID == "" {
+			msg := job.Error
+			if msg == "" {
+				msg = "job not started"
+			}
+			item.Err = errors.New(msg)
+			continue
+		}
+
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			select {
+			case sem <- struct{}{}:
+				defer func() { <-sem }()
+			case <-ctx.Done():
+				item.Err = ctx.Err()
+				return
+			}
+
+			item.Response, item.Err = c.WaitForCompletion(ctx, item.RequestID, 0, opts...)
+		}()
+	}
+
+	wg.Wait()
+
+	return result, ctx.Err()
+}
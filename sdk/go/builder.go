@@ -0,0 +1,415 @@
+package scrapeapi
+
+import (
+	"maps"
+	"time"
+)
+
+// RequestBuilder assembles a ScrapeRequest fluently:
+//
+//	req, err := scrapeapi.NewRequest(scrapeapi.GraphSmart).
+//		URL("https://example.com/jobs").
+//		Prompt("Extract all job listings").
+//		Schema(schema).
+//		Model("openai/gpt-4o-mini").
+//		Temperature(0).
+//		Build()
+type RequestBuilder struct {
+	req ScrapeRequest
+}
+
+// NewRequest starts building a request for graph
+func NewRequest(graph Graph) *RequestBuilder {
+	return &RequestBuilder{req: ScrapeRequest{Graph: graph}}
+}
+
+// URL sets the page to scrape
+func (b *RequestBuilder) URL(u string) *RequestBuilder {
+	b.req.WebsiteURL = String(u)
+	return b
+}
+
+// HTML sets raw HTML to scrape instead of a URL
+func (b *RequestBuilder) HTML(html string) *RequestBuilder {
+	b.req.WebsiteHTML = String(html)
+	return b
+}
+
+// Document sets a PDF or DOCX file as the source
+func (b *RequestBuilder) Document(url string) *RequestBuilder {
+	b.req.Document = &DocumentSource{URL: url}
+	return b
+}
+
+// Sources adds URLs for the multi graph
+func (b *RequestBuilder) Sources(urls ...string) *RequestBuilder {
+	b.req.Sources = append(b.req.Sources, URLs(urls...)...)
+	return b
+}
+
+// Source adds a source with options of its own for the multi graph
+func (b *RequestBuilder) Source(src Source) *RequestBuilder {
+	src.Headers = maps.Clone(src.Headers)
+	b.req.Sources = append(b.req.Sources, src)
+	return b
+}
+
+// Confidence makes the server return a confidence score with every extracted value
+func (b *RequestBuilder) Confidence() *RequestBuilder {
+	b.req.IncludeConfidence = true
+	return b
+}
+
+// Attribution annotates the response with the source of every extracted item
+func (b *RequestBuilder) Attribution() *RequestBuilder {
+	b.req.IncludeAttribution = true
+	return b
+}
+
+// Merge sets how the results of multiple sources are combined
+func (b *RequestBuilder) Merge(strategy MergeStrategy, key string) *RequestBuilder {
+	b.req.Merge = &MergeConfig{Strategy: strategy, Key: key}
+	return b
+}
+
+// SearchQuery sets the query for the search graph
+func (b *RequestBuilder) SearchQuery(q string) *RequestBuilder {
+	b.req.SearchQuery = String(q)
+	return b
+}
+
+// MaxResults caps the number of search results
+func (b *RequestBuilder) MaxResults(n int) *RequestBuilder {
+	b.req.MaxResults = Int(n)
+	return b
+}
+
+// Search sets the engine, region, language and filters of the search graph
+func (b *RequestBuilder) Search(cfg SearchConfig) *RequestBuilder {
+	b.req.Search = &cfg
+	return b
+}
+
+// Header adds a header sent to the target website
+func (b *RequestBuilder) Header(key, value string) *RequestBuilder {
+	if b.req.Headers == nil {
+		b.req.Headers = map[string]string{}
+	}
+	b.req.Headers[key] = value
+	return b
+}
+
+// Prompt sets the instruction describing what to extract
+func (b *RequestBuilder) Prompt(p string) *RequestBuilder {
+	b.req.UserPrompt = p
+	return b
+}
+
+// Schema sets the JSON Schema of the expected output
+func (b *RequestBuilder) Schema(schema interface{}) *RequestBuilder {
+	b.req.OutputSchema = schema
+	return b
+}
+
+// LLM replaces the whole LLM configuration with a copy of cfg, so later
+// calls such as Model do not change the caller's config
+func (b *RequestBuilder) LLM(cfg *LLMConfig) *RequestBuilder {
+	b.req.LLM = cfg.clone()
+	return b
+}
+
+// LLMAlias selects an LLM configuration registered on the client by name
+func (b *RequestBuilder) LLMAlias(name string) *RequestBuilder {
+	b.req.LLMAlias = name
+	return b
+}
+
+// Model sets the LLM model, e.g. "openai/gpt-4o-mini"
+func (b *RequestBuilder) Model(model string) *RequestBuilder {
+	b.llm().Model = model
+	return b
+}
+
+// FallbackModels sets the models tried in order when the primary model fails
+func (b *RequestBuilder) FallbackModels(models ...string) *RequestBuilder {
+	b.llm().FallbackModels = append([]string(nil), models...)
+	return b
+}
+
+// APIKey sets the LLM provider API key
+func (b *RequestBuilder) APIKey(key string) *RequestBuilder {
+	b.llm().APIKey = key
+	return b
+}
+
+// APIBase sets a custom LLM API base URL
+func (b *RequestBuilder) APIBase(base string) *RequestBuilder {
+	b.llm().APIBase = base
+	return b
+}
+
+// Provider sets the LLM provider name
+func (b *RequestBuilder) Provider(provider string) *RequestBuilder {
+	b.llm().Provider = provider
+	return b
+}
+
+// Temperature sets the LLM sampling temperature; 0 is sent explicitly
+func (b *RequestBuilder) Temperature(t float64) *RequestBuilder {
+	b.llm().Temperature = Float64(t)
+	return b
+}
+
+// Embeddings sets the embedding model used for retrieval, e.g. "openai/text-embedding-3-small"
+func (b *RequestBuilder) Embeddings(model string) *RequestBuilder {
+	if b.req.Embeddings == nil {
+		b.req.Embeddings = &EmbeddingsConfig{}
+	}
+	b.req.Embeddings.Model = model
+	return b
+}
+
+// TopP sets the LLM nucleus sampling probability mass
+func (b *RequestBuilder) TopP(p float64) *RequestBuilder {
+	b.llm().TopP = Float64(p)
+	return b
+}
+
+// Seed sets the LLM sampling seed, for repeatable extraction
+func (b *RequestBuilder) Seed(seed int) *RequestBuilder {
+	b.llm().Seed = Int(seed)
+	return b
+}
+
+// Headless sets whether the browser runs headless
+func (b *RequestBuilder) Headless(headless bool) *RequestBuilder {
+	b.req.Headless = headless
+	return b
+}
+
+// Stealth enables the fingerprint-evasion browser profile
+func (b *RequestBuilder) Stealth() *RequestBuilder {
+	b.req.Stealth = true
+	return b
+}
+
+// Actions adds browser interactions run before extraction
+func (b *RequestBuilder) Actions(actions ...Action) *RequestBuilder {
+	b.req.Actions = append(b.req.Actions, actions...)
+	return b
+}
+
+// SubmitForm fills in and submits a form on the target page before extraction
+func (b *RequestBuilder) SubmitForm(cfg FormConfig) *RequestBuilder {
+	cfg.Fields = maps.Clone(cfg.Fields)
+	if cfg.WaitFor != nil {
+		w := *cfg.WaitFor
+		cfg.WaitFor = &w
+	}
+	b.req.SubmitForm = &cfg
+	return b
+}
+
+// EvaluateJS sets JavaScript run in the page before it is captured
+func (b *RequestBuilder) EvaluateJS(script string) *RequestBuilder {
+	b.req.EvaluateJS = script
+	return b
+}
+
+// Device sets the emulated viewport and device class
+func (b *RequestBuilder) Device(d *DeviceConfig) *RequestBuilder {
+	if d != nil {
+		dc := *d
+		d = &dc
+	}
+	b.req.Device = d
+	return b
+}
+
+// Geo sets the location target pages see the job coming from
+func (b *RequestBuilder) Geo(cfg GeoConfig) *RequestBuilder {
+	if cfg.Coordinates != nil {
+		c := *cfg.Coordinates
+		cfg.Coordinates = &c
+	}
+	b.req.Geo = &cfg
+	return b
+}
+
+// Locale sets the browser locale and time zone, e.g. "de-DE" and "Europe/Berlin"
+func (b *RequestBuilder) Locale(locale, timezone string) *RequestBuilder {
+	b.req.Locale = locale
+	b.req.Timezone = timezone
+	return b
+}
+
+// BlockResources skips loading the given classes of subresources
+func (b *RequestBuilder) BlockResources(resources ...ResourceType) *RequestBuilder {
+	b.req.BlockResources = append(b.req.BlockResources, resources...)
+	return b
+}
+
+// Screenshot requests a screenshot artifact of the viewport, or of the whole page if fullPage is set
+func (b *RequestBuilder) Screenshot(fullPage bool) *RequestBuilder {
+	b.req.CaptureScreenshot = true
+	b.req.ScreenshotFullPage = fullPage
+	return b
+}
+
+// PDF requests a PDF rendering of the page as an artifact
+func (b *RequestBuilder) PDF() *RequestBuilder {
+	b.req.CapturePDF = true
+	return b
+}
+
+// Pagination makes the job follow a paginated listing
+func (b *RequestBuilder) Pagination(p *PaginationConfig) *RequestBuilder {
+	if p != nil {
+		pc := *p
+		p = &pc
+	}
+	b.req.Pagination = p
+	return b
+}
+
+// Sitemap sets the page filters of the sitemap graph
+func (b *RequestBuilder) Sitemap(cfg *SitemapConfig) *RequestBuilder {
+	if cfg != nil {
+		sc := *cfg
+		sc.Include = append([]string(nil), cfg.Include...)
+		sc.Exclude = append([]string(nil), cfg.Exclude...)
+		cfg = &sc
+	}
+	b.req.Sitemap = cfg
+	return b
+}
+
+// Crawl sets the link-following limits of the crawl graph
+func (b *RequestBuilder) Crawl(cfg *CrawlConfig) *RequestBuilder {
+	if cfg != nil {
+		cc := *cfg
+		cc.Allow = append([]string(nil), cfg.Allow...)
+		cc.Deny = append([]string(nil), cfg.Deny...)
+		cfg = &cc
+	}
+	b.req.Crawl = cfg
+	return b
+}
+
+// CrawlRateLimit caps the per-host request rate of crawl and sitemap jobs
+func (b *RequestBuilder) CrawlRateLimit(requestsPerSecond float64, concurrency int) *RequestBuilder {
+	b.req.CrawlRateLimit = &HostRateLimit{RequestsPerSecond: requestsPerSecond, Concurrency: concurrency}
+	return b
+}
+
+// OutputLanguage sets the language free-text result fields are written in, e.g. "en"
+func (b *RequestBuilder) OutputLanguage(lang string) *RequestBuilder {
+	b.req.OutputLanguage = lang
+	return b
+}
+
+// OutputFormat sets whether the job extracts data or returns the page as markdown or text
+func (b *RequestBuilder) OutputFormat(f OutputFormat) *RequestBuilder {
+	b.req.OutputFormat = f
+	return b
+}
+
+// Feed sets how the feed graph handles feed entries
+func (b *RequestBuilder) Feed(cfg FeedConfig) *RequestBuilder {
+	b.req.Feed = &cfg
+	return b
+}
+
+// Images lists the page images in the response, additionally reading text from them if ocr is set
+func (b *RequestBuilder) Images(ocr bool) *RequestBuilder {
+	b.req.ExtractImages = true
+	b.req.OCR = ocr
+	return b
+}
+
+// Dedupe merges duplicate items by the given key fields
+func (b *RequestBuilder) Dedupe(threshold float64, keyFields ...string) *RequestBuilder {
+	b.req.Dedupe = &DedupeConfig{KeyFields: append([]string(nil), keyFields...), Threshold: threshold}
+	return b
+}
+
+// LoaderKwargs sets browser loader options
+func (b *RequestBuilder) LoaderKwargs(kwargs interface{}) *RequestBuilder {
+	b.req.LoaderKwargs = kwargs
+	return b
+}
+
+// Verbose enables server-side debug logging
+func (b *RequestBuilder) Verbose(verbose bool) *RequestBuilder {
+	b.req.Verbose = verbose
+	return b
+}
+
+// Additional sets free-form extra graph configuration
+func (b *RequestBuilder) Additional(cfg interface{}) *RequestBuilder {
+	b.req.Additional = cfg
+	return b
+}
+
+// Budget caps the LLM spend of the job; a zero cap is left unset
+func (b *RequestBuilder) Budget(maxCostUSD float64, maxTokens int) *RequestBuilder {
+	if maxCostUSD != 0 {
+		b.req.MaxCostUSD = Float64(maxCostUSD)
+	}
+	if maxTokens != 0 {
+		b.req.MaxTokens = Int(maxTokens)
+	}
+	return b
+}
+
+// Timeout sets the server-side job timeout, rounded down to whole seconds
+func (b *RequestBuilder) Timeout(d time.Duration) *RequestBuilder {
+	b.req.TimeoutSec = int(d / time.Second)
+	return b
+}
+
+// Tags adds labels for filtering with ListScrapes
+func (b *RequestBuilder) Tags(tags ...string) *RequestBuilder {
+	b.req.Tags = append(b.req.Tags, tags...)
+	return b
+}
+
+// Cache reuses results of the same request cached within ttl seconds
+func (b *RequestBuilder) Cache(ttlSec int) *RequestBuilder {
+	b.req.Cache = &CacheConfig{TTLSec: ttlSec}
+	return b
+}
+
+// CallbackURL sets the webhook notified when the job finishes
+func (b *RequestBuilder) CallbackURL(u string) *RequestBuilder {
+	b.req.CallbackURL = String(u)
+	return b
+}
+
+// Build validates and returns the request. The builder can keep being used
+// afterwards without affecting the returned request's top-level fields.
+func (b *RequestBuilder) Build() (*ScrapeRequest, error) {
+	req := b.req
+	req.LLM = b.req.LLM.clone()
+	if b.req.Embeddings != nil {
+		embeddings := *b.req.Embeddings
+		req.Embeddings = &embeddings
+	}
+	req.Sources = append([]Source(nil), b.req.Sources...)
+	req.Tags = append([]string(nil), b.req.Tags...)
+	req.Headers = maps.Clone(b.req.Headers)
+	req.Actions = append([]Action(nil), b.req.Actions...)
+	req.BlockResources = append([]ResourceType(nil), b.req.BlockResources...)
+
+	if err := req.Validate(); err != nil {
+		return nil, err
+	}
+	return &req, nil
+}
+
+func (b *RequestBuilder) llm() *LLMConfig {
+	if b.req.LLM == nil {
+		b.req.LLM = &LLMConfig{}
+	}
+	return b.req.LLM
+}
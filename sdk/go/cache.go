@@ -0,0 +1,20 @@
+package scrapeapi
+
+// CacheConfig controls the server-side result cache. Jobs for the same
+// request within the TTL return the cached result instead of scraping again;
+// ScrapeResponse.CacheHit reports when that happened.
+type CacheConfig struct {
+	// TTLSec is how long a result stays cached; the server default applies when zero
+	TTLSec int `json:"ttl_sec,omitempty"`
+	// Bypass always scrapes afresh, still refreshing the cache with the new result
+	Bypass bool `json:"bypass,omitempty"`
+	// Key replaces the cache key the server derives from the URL, prompt and
+	// schema, e.g. to share results between requests with different prompts
+	Key string `json:"key,omitempty"`
+}
+
+func (c *CacheConfig) validate(v *validator, field string) {
+	if c.TTLSec < 0 {
+		v.addf(field+".ttl_sec", "must not be negative, got %d", c.TTLSec)
+	}
+}
@@ -4,16 +4,21 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
-	"log"
+	"io"
+	"log/slog"
 	"net/http"
-	"strings"
+	"net/url"
+	"sync"
 	"time"
 
 	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
 	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
 	"go.opentelemetry.io/otel/propagation"
 	"go.opentelemetry.io/otel/trace"
+	"go.opentelemetry.io/otel/trace/noop"
 )
 
 // Client represents a ScrapeAPI client
@@ -21,39 +26,124 @@ type Client struct {
 	BaseURL    string
 	HTTPClient *http.Client
 	tracer     trace.Tracer
+	logger     *slog.Logger
+
+	tracerProvider  trace.TracerProvider
+	tracingDisabled bool
+	propagator      propagation.TextMapPropagator
+	retryPolicy     RetryPolicy
+	defaultHeaders  http.Header
+	robots          *robotsChecker
+	hooks           []Hooks
+	debugDump       *debugDumper
+	limiter         *rateLimiter
+
+	llmMu      sync.RWMutex
+	defaultLLM *LLMConfig
+	llmAliases map[string]*LLMConfig
 }
 
 // NewClient creates a new ScrapeAPI client with OpenTelemetry instrumentation
-func NewClient(baseURL string) *Client {
+func NewClient(baseURL string, opts ...ClientOption) *Client {
+	c := &Client{
+		BaseURL: baseURL,
+		logger:  slog.New(discardHandler{}),
+	}
+
+	for _, opt := range opts {
+		opt(c)
+	}
+
+	if c.propagator == nil {
+		c.propagator = propagation.NewCompositeTextMapPropagator(propagation.TraceContext{}, otel.GetTextMapPropagator())
+	}
+
+	var transport http.RoundTripper = http.DefaultTransport
+	switch {
+	case c.tracingDisabled:
+		c.tracerProvider = noop.NewTracerProvider()
+	case c.tracerProvider == nil:
+		c.tracerProvider = otel.GetTracerProvider()
+		transport = otelhttp.NewTransport(transport, otelhttp.WithPropagators(c.propagator))
+	default:
+		transport = otelhttp.NewTransport(transport,
+			otelhttp.WithTracerProvider(c.tracerProvider),
+			otelhttp.WithPropagators(c.propagator),
+		)
+	}
+	c.tracer = c.tracerProvider.Tracer("scrapeapi-sdk")
+
 	// Create HTTP client with OpenTelemetry transport instrumentation
-	httpClient := &http.Client{
+	c.HTTPClient = &http.Client{
 		Timeout:   30 * time.Second,
-		Transport: otelhttp.NewTransport(http.DefaultTransport),
-	}
-	
-	return &Client{
-		BaseURL:    baseURL,
-		HTTPClient: httpClient,
-		tracer:     otel.Tracer("scrapeapi-sdk"),
+		Transport: transport,
 	}
+
+	return c
 }
 
 // ScrapeRequest represents a scraping request
 type ScrapeRequest struct {
-	Graph        string      `json:"graph"`
+	Graph        Graph       `json:"graph"`
 	UserPrompt   string      `json:"user_prompt"`
 	WebsiteURL   *string     `json:"website_url,omitempty"`
 	WebsiteHTML  *string     `json:"website_html,omitempty"`
-	Sources      []string    `json:"sources,omitempty"`
+	Sources      []Source    `json:"sources,omitempty"`
 	SearchQuery  *string     `json:"search_query,omitempty"`
 	MaxResults   *int        `json:"max_results,omitempty"`
 	OutputSchema interface{} `json:"output_schema,omitempty"`
 	LLM          *LLMConfig  `json:"llm,omitempty"`
-	Headless     bool        `json:"headless,omitempty"`
-	LoaderKwargs interface{} `json:"loader_kwargs,omitempty"`
-	Verbose      bool        `json:"verbose,omitempty"`
-	Additional   interface{} `json:"additional_config,omitempty"`
-	TimeoutSec   int         `json:"timeout_sec,omitempty"`
+	// LLMAlias selects an LLM configuration registered with Client.SetLLMAlias
+	// when LLM is nil; it is resolved by the client and not sent
+	LLMAlias           string            `json:"-"`
+	Embeddings         *EmbeddingsConfig `json:"embeddings,omitempty"`
+	Headless           bool              `json:"headless,omitempty"`
+	LoaderKwargs       interface{}       `json:"loader_kwargs,omitempty"`
+	Verbose            bool              `json:"verbose,omitempty"`
+	Additional         interface{}       `json:"additional_config,omitempty"`
+	TimeoutSec         int               `json:"timeout_sec,omitempty"`
+	Tags               []string          `json:"tags,omitempty"`
+	CallbackURL        *string           `json:"callback_url,omitempty"`
+	Headers            map[string]string `json:"headers,omitempty"`
+	Cookies            []Cookie          `json:"cookies,omitempty"`
+	Proxy              *ProxyConfig      `json:"proxy,omitempty"`
+	Stealth            bool              `json:"stealth,omitempty"`
+	Actions            []Action          `json:"actions,omitempty"`
+	WaitFor            *WaitCondition    `json:"wait_for,omitempty"`
+	EvaluateJS         string            `json:"evaluate_js,omitempty"`
+	Device             *DeviceConfig     `json:"device,omitempty"`
+	CaptureScreenshot  bool              `json:"capture_screenshot,omitempty"`
+	ScreenshotFullPage bool              `json:"screenshot_full_page,omitempty"`
+	CapturePDF         bool              `json:"capture_pdf,omitempty"`
+	Pagination         *PaginationConfig `json:"pagination,omitempty"`
+	Sitemap            *SitemapConfig    `json:"sitemap,omitempty"`
+	Crawl              *CrawlConfig      `json:"crawl,omitempty"`
+	RespectRobotsTxt   bool              `json:"respect_robots_txt,omitempty"`
+	CrawlRateLimit     *HostRateLimit    `json:"crawl_rate_limit,omitempty"`
+	Search             *SearchConfig     `json:"search,omitempty"`
+	OutputFormat       OutputFormat      `json:"output_format,omitempty"`
+	Feed               *FeedConfig       `json:"feed,omitempty"`
+	Document           *DocumentSource   `json:"document,omitempty"`
+	ExtractImages      bool              `json:"extract_images,omitempty"`
+	OCR                bool              `json:"ocr,omitempty"`
+	Dedupe             *DedupeConfig     `json:"dedupe,omitempty"`
+	Geo                *GeoConfig        `json:"geo,omitempty"`
+	Locale             string            `json:"locale,omitempty"`
+	Timezone           string            `json:"timezone,omitempty"`
+	Auth               *SiteAuthConfig   `json:"auth,omitempty"`
+	SubmitForm         *FormConfig       `json:"submit_form,omitempty"`
+	Captcha            *CaptchaConfig    `json:"captcha,omitempty"`
+	Cache              *CacheConfig      `json:"cache,omitempty"`
+	BlockResources     []ResourceType    `json:"block_resources,omitempty"`
+	MaxCostUSD         *float64          `json:"max_cost_usd,omitempty"`
+	MaxTokens          *int              `json:"max_tokens,omitempty"`
+	OutputLanguage     string            `json:"output_language,omitempty"`
+	Merge              *MergeConfig      `json:"merge,omitempty"`
+	IncludeAttribution bool              `json:"include_attribution,omitempty"`
+	IncludeConfidence  bool              `json:"include_confidence,omitempty"`
+	// TraceContext carries the W3C trace context of the submitting span to the
+	// server, which runs the job asynchronously; the client sets it when nil
+	TraceContext map[string]string `json:"trace_context,omitempty"`
 }
 
 // LLMConfig represents LLM configuration
@@ -63,187 +153,553 @@ type LLMConfig struct {
 	APIBase     string   `json:"api_base,omitempty"`
 	Temperature *float64 `json:"temperature,omitempty"`
 	Provider    string   `json:"provider,omitempty"`
-}
-
-// ScrapeResponse represents the API response
-type ScrapeResponse struct {
-	RequestID  string      `json:"request_id"`
-	Status     string      `json:"status"`
-	Graph      string      `json:"graph"`
-	UserPrompt string      `json:"user_prompt"`
-	WebsiteURL *string     `json:"website_url,omitempty"`
-	Sources    []string    `json:"sources,omitempty"`
-	Result     interface{} `json:"result,omitempty"`
-	Error      string      `json:"error,omitempty"`
+	// TopP is the nucleus sampling probability mass, in (0, 1]
+	TopP *float64 `json:"top_p,omitempty"`
+	// MaxTokens caps the completion of each LLM call, unlike ScrapeRequest.MaxTokens
+	// which caps the whole job
+	MaxTokens *int `json:"max_tokens,omitempty"`
+	// Seed makes sampling repeatable on providers that support it
+	Seed *int `json:"seed,omitempty"`
+	// FrequencyPenalty discourages repeated tokens, in [-2, 2]
+	FrequencyPenalty *float64 `json:"frequency_penalty,omitempty"`
+	// StopSequences end the completion when generated
+	StopSequences []string `json:"stop,omitempty"`
+	// FallbackModels are tried in order when the model before them errors or is rate-limited
+	FallbackModels []string `json:"fallback_models,omitempty"`
+	// AzureEndpoint, AzureDeployment and APIVersion address an Azure OpenAI
+	// deployment; see AzureOpenAILLM
+	AzureEndpoint   string `json:"azure_endpoint,omitempty"`
+	AzureDeployment string `json:"azure_deployment,omitempty"`
+	APIVersion      string `json:"api_version,omitempty"`
+	// Bedrock routes the model through AWS Bedrock; see BedrockLLM
+	Bedrock *BedrockConfig `json:"bedrock,omitempty"`
 }
 
 // StartScrape initiates a scraping job with tracing
-func (c *Client) StartScrape(ctx context.Context, req *ScrapeRequest) (*ScrapeResponse, error) {
-	// Check incoming context
-	incomingSpan := trace.SpanFromContext(ctx)
-	log.Printf("🔧 SDK StartScrape: Incoming context span valid: %v", incomingSpan.SpanContext().IsValid())
-	if incomingSpan.SpanContext().IsValid() {
-		log.Printf("🔧 SDK StartScrape: Incoming trace ID: %s", incomingSpan.SpanContext().TraceID().String())
-		log.Printf("🔧 SDK StartScrape: Incoming span sampled: %v", incomingSpan.SpanContext().IsSampled())
-	}
+func (c *Client) StartScrape(ctx context.Context, req *ScrapeRequest, opts ...RequestOption) (*ScrapeResponse, error) {
+	c.logSpanContext(ctx, "StartScrape: incoming context", trace.SpanFromContext(ctx))
 
 	// Create a span for this operation
 	// If there's no existing span in context, this creates a new root span
 	ctx, span := c.tracer.Start(ctx, "scrapeapi.StartScrape")
 	defer span.End()
-	
-	log.Printf("🔧 SDK StartScrape: Created span trace ID: %s", span.SpanContext().TraceID().String())
-	log.Printf("🔧 SDK StartScrape: Created span valid: %v", span.SpanContext().IsValid())
-	log.Printf("🔧 SDK StartScrape: Created span sampled: %v", span.SpanContext().IsSampled())
 
-	jsonData, err := json.Marshal(req)
-	if err != nil {
-		return nil, fmt.Errorf("marshal request: %w", err)
-	}
+	c.logSpanContext(ctx, "StartScrape: created span", span)
 
-	httpReq, err := http.NewRequestWithContext(ctx, "POST", c.BaseURL+"/v1/scrape", bytes.NewBuffer(jsonData))
+	req, err := c.resolveLLM(req)
 	if err != nil {
-		return nil, fmt.Errorf("create request: %w", err)
+		return nil, err
 	}
-	httpReq.Header.Set("Content-Type", "application/json")
-	
-	// Manual trace context injection as fallback (since otelhttp isn't working)
-	propagator := propagation.TraceContext{}
-	propagator.Inject(ctx, propagation.HeaderCarrier(httpReq.Header))
-	
-	// Debug: Log ALL outgoing headers to see what was injected
-	log.Printf("🔧 SDK StartScrape: ALL outgoing headers after injection:")
-	for key, values := range httpReq.Header {
-		log.Printf("  %s: %s", key, strings.Join(values, ", "))
+	span.SetAttributes(requestAttributes(req)...)
+	if err := req.Validate(); err != nil {
+		return nil, err
 	}
-
-	resp, err := c.HTTPClient.Do(httpReq)
-	if err != nil {
-		return nil, fmt.Errorf("execute request: %w", err)
+	if err := c.checkRobots(ctx, req); err != nil {
+		return nil, err
 	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("API error: %s", resp.Status)
+	req = c.withTraceContext(ctx, req)
+	if c.retryPolicy.MaxAttempts > 1 {
+		opts = withIdempotencyKey(opts)
 	}
 
 	var scrapeResp ScrapeResponse
-	if err := json.NewDecoder(resp.Body).Decode(&scrapeResp); err != nil {
-		return nil, fmt.Errorf("decode response: %w", err)
+	if err := c.do(ctx, http.MethodPost, "/v1/scrape", req, &scrapeResp, opts...); err != nil {
+		return nil, err
 	}
+	recordResponse(span, &scrapeResp)
 
 	return &scrapeResp, nil
 }
 
 // GetScrape polls for the status of a scraping job with tracing
-func (c *Client) GetScrape(ctx context.Context, requestID string) (*ScrapeResponse, error) {
+func (c *Client) GetScrape(ctx context.Context, requestID string, opts ...RequestOption) (*ScrapeResponse, error) {
 	// Create a span for this operation
 	// If there's no existing span in context, this creates a new root span
 	ctx, span := c.tracer.Start(ctx, "scrapeapi.GetScrape")
 	defer span.End()
+	span.SetAttributes(attrRequestID.String(requestID))
 
-	httpReq, err := http.NewRequestWithContext(ctx, "GET", c.BaseURL+"/v1/scrape/"+requestID, nil)
+	var scrapeResp ScrapeResponse
+	if err := c.do(ctx, http.MethodGet, "/v1/scrape/"+url.PathEscape(requestID), nil, &scrapeResp, opts...); err != nil {
+		return nil, err
+	}
+	recordResponse(span, &scrapeResp)
+
+	return &scrapeResp, nil
+}
+
+// CancelScrape asks the server to stop a queued or running scraping job
+func (c *Client) CancelScrape(ctx context.Context, requestID string, opts ...RequestOption) (*ScrapeResponse, error) {
+	// Create a span for this operation
+	// If there's no existing span in context, this creates a new root span
+	ctx, span := c.tracer.Start(ctx, "scrapeapi.CancelScrape")
+	defer span.End()
+	span.SetAttributes(attrRequestID.String(requestID))
+
+	var scrapeResp ScrapeResponse
+	// Canceling twice has no further effect
+	opts = append(opts, idempotentCall())
+	if err := c.do(ctx, http.MethodPost, "/v1/scrape/"+url.PathEscape(requestID)+"/cancel", nil, &scrapeResp, opts...); err != nil {
+		return nil, err
+	}
+	recordResponse(span, &scrapeResp)
+
+	return &scrapeResp, nil
+}
+
+// DeleteScrape removes a job and its payload and result from the server
+func (c *Client) DeleteScrape(ctx context.Context, requestID string, opts ...RequestOption) error {
+	// Create a span for this operation
+	// If there's no existing span in context, this creates a new root span
+	ctx, span := c.tracer.Start(ctx, "scrapeapi.DeleteScrape")
+	defer span.End()
+	span.SetAttributes(attrRequestID.String(requestID))
+
+	return c.do(ctx, http.MethodDelete, "/v1/scrape/"+url.PathEscape(requestID), nil, nil, opts...)
+}
+
+// do sends a request to the API and decodes the JSON response into out.
+// Non-2xx responses are returned as *APIError. Transient failures are retried
+// according to the client's retry policy; POSTs only if they carry an
+// Idempotency-Key or are marked with idempotentCall.
+func (c *Client) do(ctx context.Context, method, path string, body, out interface{}, opts ...RequestOption) error {
+	var jsonData []byte
+	if body != nil {
+		var err error
+		jsonData, err = json.Marshal(body)
+		if err != nil {
+			return fmt.Errorf("marshal request: %w", err)
+		}
+	}
+
+	// A POST that failed in transit may still have taken effect, so it is
+	// only sent again if the server can tell the attempts apart
+	maxAttempts := c.retryPolicy.MaxAttempts
+	if cfg := newRequestConfig(opts); method == http.MethodPost && !cfg.idempotent && cfg.header.Get(headerIdempotencyKey) == "" {
+		maxAttempts = 1
+	}
+
+	span := trace.SpanFromContext(ctx)
+	for attempt := 1; ; attempt++ {
+		err := c.doOnce(ctx, method, path, jsonData, out, opts)
+		if err == nil || attempt >= maxAttempts || !c.retryPolicy.shouldRetry(err) {
+			return err
+		}
+
+		delay := c.retryPolicy.backoff(attempt)
+		var apiErr *APIError
+		if errors.As(err, &apiErr) && apiErr.RetryAfter > delay {
+			delay = apiErr.RetryAfter
+		}
+
+		span.AddEvent("retry", trace.WithAttributes(
+			attribute.Int("retry.attempt", attempt),
+			attribute.String("retry.delay", delay.String()),
+			attribute.String("retry.error", err.Error()),
+		))
+		c.hookRetry(ctx, method, path, attempt, delay, err)
+		c.logger.DebugContext(ctx, "retrying request",
+			"method", method,
+			"path", path,
+			"attempt", attempt,
+			"delay", delay,
+			"error", err,
+		)
+
+		if err := sleep(ctx, delay); err != nil {
+			return err
+		}
+	}
+}
+
+// doOnce performs a single attempt of an API call
+func (c *Client) doOnce(ctx context.Context, method, path string, jsonData []byte, out interface{}, opts []RequestOption) error {
+	var reqBody io.Reader
+	if jsonData != nil {
+		reqBody = bytes.NewReader(jsonData)
+	}
+
+	httpReq, err := c.newRequest(ctx, method, path, reqBody, opts...)
 	if err != nil {
-		return nil, fmt.Errorf("create request: %w", err)
+		return err
+	}
+	if jsonData != nil {
+		httpReq.Header.Set("Content-Type", "application/json")
 	}
+	httpReq.Header.Set("Accept", "application/json")
+	c.debugDump.request(httpReq, jsonData)
 
-	resp, err := c.HTTPClient.Do(httpReq)
+	resp, err := c.send(c.HTTPClient, httpReq)
 	if err != nil {
-		return nil, fmt.Errorf("execute request: %w", err)
+		var apiErr *APIError
+		if errors.As(err, &apiErr) {
+			c.debugDump.response(httpReq, apiErr.Status, nil, apiErr.Body)
+		}
+		return err
 	}
 	defer resp.Body.Close()
 
-	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("API error: %s", resp.Status)
+	var body io.Reader = resp.Body
+	if c.debugDump != nil {
+		raw, err := io.ReadAll(resp.Body)
+		if err != nil {
+			return fmt.Errorf("read response: %w", err)
+		}
+		c.debugDump.response(httpReq, resp.Status, resp.Header, raw)
+		body = bytes.NewReader(raw)
 	}
 
-	var scrapeResp ScrapeResponse
-	if err := json.NewDecoder(resp.Body).Decode(&scrapeResp); err != nil {
-		return nil, fmt.Errorf("decode response: %w", err)
+	if out == nil {
+		return nil
+	}
+	if err := json.NewDecoder(body).Decode(out); err != nil {
+		return fmt.Errorf("decode response: %w", err)
 	}
 
-	return &scrapeResp, nil
+	return nil
 }
 
-// WaitForCompletion waits for a scraping job to complete with polling and tracing
-func (c *Client) WaitForCompletion(ctx context.Context, requestID string, pollInterval time.Duration) (*ScrapeResponse, error) {
+// newRequest builds an API request carrying the client's default headers,
+// the headers set by opts and the trace context of ctx
+func (c *Client) newRequest(ctx context.Context, method, path string, body io.Reader, opts ...RequestOption) (*http.Request, error) {
+	httpReq, err := http.NewRequestWithContext(ctx, method, c.BaseURL+path, body)
+	if err != nil {
+		return nil, fmt.Errorf("create request: %w", err)
+	}
+
+	c.setHeaders(ctx, httpReq.Header, opts...)
+
+	return httpReq, nil
+}
+
+// setHeaders adds the headers an API call carries to h
+func (c *Client) setHeaders(ctx context.Context, h http.Header, opts ...RequestOption) {
+	for key, values := range c.defaultHeaders {
+		h[key] = append([]string(nil), values...)
+	}
+
+	cfg := newRequestConfig(opts)
+	for key, values := range cfg.header {
+		h[key] = append([]string(nil), values...)
+	}
+
+	// Manual trace context injection as fallback (since otelhttp isn't working)
+	c.propagator.Inject(ctx, propagation.HeaderCarrier(h))
+}
+
+// longLivedClient returns a copy of HTTPClient without the client-wide timeout,
+// for streams and downloads whose duration is bounded by their context instead
+func (c *Client) longLivedClient() *http.Client {
+	hc := *c.HTTPClient
+	hc.Timeout = 0
+	return &hc
+}
+
+// send executes an API request with hc. Non-2xx responses are returned as *APIError.
+// On success the caller must close the response body.
+func (c *Client) send(hc *http.Client, httpReq *http.Request) (*http.Response, error) {
+	c.logger.DebugContext(httpReq.Context(), "outgoing request",
+		"method", httpReq.Method,
+		"url", redactURL(httpReq.URL.String()),
+		"headers", redactHeader(httpReq.Header),
+	)
+
+	ctx := httpReq.Context()
+	if err := c.waitRateLimit(ctx); err != nil {
+		return nil, err
+	}
+	c.hookRequest(ctx, httpReq)
+	start := time.Now()
+	resp, err := hc.Do(httpReq)
+	c.hookResponse(ctx, httpReq, resp, time.Since(start), err)
+	if err != nil {
+		return nil, &transportError{err: err}
+	}
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		defer resp.Body.Close()
+		return nil, newAPIError(resp)
+	}
+
+	return resp, nil
+}
+
+// WaitForCompletion waits for a scraping job to complete with polling and tracing.
+// A positive pollInterval polls at that fixed rate; zero polls adaptively (see WithPollBackoff).
+func (c *Client) WaitForCompletion(ctx context.Context, requestID string, pollInterval time.Duration, opts ...WaitOption) (*ScrapeResponse, error) {
 	// Create a span for this operation
 	// If there's no existing span in context, this creates a new root span
 	ctx, span := c.tracer.Start(ctx, "scrapeapi.WaitForCompletion")
 	defer span.End()
 
-	ticker := time.NewTicker(pollInterval)
-	defer ticker.Stop()
+	cfg := &waitConfig{
+		pollInterval: pollInterval,
+	}
+
+	for _, opt := range opts {
+		opt(cfg)
+	}
+
+	waitCtx := ctx
+	if deadline, ok := cfg.deadlineFrom(time.Now()); ok {
+		var cancel context.CancelFunc
+		waitCtx, cancel = context.WithDeadline(ctx, deadline)
+		defer cancel()
+	}
+
+	span.SetAttributes(attrRequestID.String(requestID))
+	state := &waitState{span: span, onChange: func(from Status, resp *ScrapeResponse) {
+		c.hookStatusChange(ctx, from, resp)
+	}}
+	resp, err := c.wait(waitCtx, requestID, cfg, state)
+
+	last := state.last
+	span.SetAttributes(attrPollCount.Int(state.polls))
+	if last != nil {
+		recordResponse(span, last)
+	}
+	if err != nil && ctx.Err() == nil && waitCtx.Err() != nil {
+		// The wait's own deadline passed, not the caller's
+		timeoutErr := &WaitTimeoutError{RequestID: requestID, LastResponse: last}
+		if last != nil {
+			timeoutErr.LastStatus = last.Status
+		}
+		return last, timeoutErr
+	}
+
+	return resp, err
+}
+
+// wait polls or streams until the job finishes, recording every update in state
+func (c *Client) wait(ctx context.Context, requestID string, cfg *waitConfig, state *waitState) (*ScrapeResponse, error) {
+	if cfg.streaming {
+		resp, err := c.waitStreaming(ctx, requestID, cfg, state)
+		switch {
+		case IsStreamingUnsupported(err):
+			c.logger.DebugContext(ctx, "event stream not supported, falling back to polling", "request_id", requestID)
+		case errors.Is(err, ErrStreamEnded):
+			c.logger.DebugContext(ctx, "event stream ended early, falling back to polling", "request_id", requestID)
+		default:
+			return resp, err
+		}
+	}
+
+	timer := time.NewTimer(cfg.pollDelay(0))
+	defer timer.Stop()
 
-	for {
+	for poll := 1; ; poll++ {
 		select {
 		case <-ctx.Done():
+			if cfg.cancelOnDone {
+				c.cancelAbandoned(ctx, requestID, cfg.requestOpts)
+			}
 			return nil, ctx.Err()
-		case <-ticker.C:
-			resp, err := c.GetScrape(ctx, requestID)
+		case <-timer.C:
+			resp, err := c.GetScrape(ctx, requestID, cfg.requestOpts...)
 			if err != nil {
 				return nil, err
 			}
+			state.observe(resp, true)
+			cfg.reportProgress(resp)
 
-			switch resp.Status {
-			case "completed":
-				return resp, nil
-			case "failed":
-				return resp, fmt.Errorf("scraping failed: %s", resp.Error)
-			case "queued", "running":
-				// Continue polling
-				continue
-			default:
-				return resp, fmt.Errorf("unknown status: %s", resp.Status)
+			if done, err := finished(resp); done {
+				return resp, err
 			}
+			timer.Reset(cfg.pollDelay(poll))
 		}
 	}
 }
 
+// waitStreaming waits for a job using its event stream
+func (c *Client) waitStreaming(ctx context.Context, requestID string, cfg *waitConfig, state *waitState) (*ScrapeResponse, error) {
+	sub, err := c.SubscribeScrape(ctx, requestID, cfg.requestOpts...)
+	if err != nil {
+		return nil, err
+	}
+	defer sub.Close()
+
+	for update := range sub.Events() {
+		state.observe(&update, false)
+		cfg.reportProgress(&update)
+		if done, err := finished(&update); done {
+			return &update, err
+		}
+	}
+
+	if ctx.Err() != nil && cfg.cancelOnDone {
+		c.cancelAbandoned(ctx, requestID, cfg.requestOpts)
+	}
+	return nil, sub.Err()
+}
+
+// finished reports whether resp is the last status of a job,
+// with the error describing a failed or unexpected outcome
+func finished(resp *ScrapeResponse) (bool, error) {
+	switch resp.Status {
+	case StatusCompleted:
+		return true, nil
+	case StatusFailed, StatusCanceled:
+		return true, newJobError(resp)
+	case StatusQueued, StatusRunning:
+		// Continue waiting
+		return false, nil
+	default:
+		return true, fmt.Errorf("unknown status: %s", resp.Status)
+	}
+}
+
+// cancelAbandoned cancels a server job whose waiter has given up.
+// It runs detached from ctx, which is already done at this point.
+func (c *Client) cancelAbandoned(ctx context.Context, requestID string, opts []RequestOption) {
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), 10*time.Second)
+	defer cancel()
+
+	if _, err := c.CancelScrape(ctx, requestID, opts...); err != nil {
+		c.logger.WarnContext(ctx, "cancel abandoned job", "request_id", requestID, "error", err)
+	}
+}
+
 // WaitOption is a functional option for configuring wait behavior
 type WaitOption func(*waitConfig)
 
 type waitConfig struct {
 	pollInterval time.Duration
+	pollMin      time.Duration
+	pollMax      time.Duration
+	pollFactor   float64
+	cancelOnDone bool
+	streaming    bool
+	onProgress   ProgressFunc
+	maxWait      time.Duration
+	deadline     time.Time
+
+	validateResult bool
+	requestOpts    []RequestOption
+}
+
+// deadlineFrom returns when a wait starting at start must end, if it is bounded
+func (cfg *waitConfig) deadlineFrom(start time.Time) (time.Time, bool) {
+	deadline := cfg.deadline
+	if cfg.maxWait > 0 {
+		if d := start.Add(cfg.maxWait); deadline.IsZero() || d.Before(deadline) {
+			deadline = d
+		}
+	}
+	return deadline, !deadline.IsZero()
 }
 
-// WithPollInterval sets the polling interval for waiting operations
+// WithPollInterval polls at a fixed interval instead of the adaptive default
 func WithPollInterval(interval time.Duration) WaitOption {
 	return func(cfg *waitConfig) {
 		cfg.pollInterval = interval
 	}
 }
 
+// WithMaxWait bounds how long to wait for a job. When it elapses, waiting
+// ends with a *WaitTimeoutError (matching ErrWaitTimeout) carrying the last known status.
+// The server-side job keeps running unless WithCancelOnContextDone is also given.
+func WithMaxWait(d time.Duration) WaitOption {
+	return func(cfg *waitConfig) {
+		cfg.maxWait = d
+	}
+}
+
+// WithDeadline is like WithMaxWait but ends the wait at an absolute time
+func WithDeadline(t time.Time) WaitOption {
+	return func(cfg *waitConfig) {
+		cfg.deadline = t
+	}
+}
+
+// WithCancelOnContextDone cancels the server-side job when the wait is
+// abandoned because ctx was canceled or timed out, or a WithMaxWait or
+// WithDeadline limit passed
+func WithCancelOnContextDone() WaitOption {
+	return func(cfg *waitConfig) {
+		cfg.cancelOnDone = true
+	}
+}
+
+// WithStreaming waits using the server's Server-Sent Events stream instead of polling.
+// If the server does not support streaming, or closes the stream before the
+// job finishes, waiting falls back to polling.
+func WithStreaming() WaitOption {
+	return func(cfg *waitConfig) {
+		cfg.streaming = true
+	}
+}
+
+// WithRequestOptions applies opts to every API call made while waiting,
+// and to the StartScrape call of ScrapeAndWait
+func WithRequestOptions(opts ...RequestOption) WaitOption {
+	return func(cfg *waitConfig) {
+		cfg.requestOpts = append(cfg.requestOpts, opts...)
+	}
+}
+
 // ScrapeAndWait is a convenience method that starts a scrape job and waits for completion with tracing
 func (c *Client) ScrapeAndWait(ctx context.Context, req *ScrapeRequest, opts ...WaitOption) (*ScrapeResponse, error) {
-	// Check incoming context
-	incomingSpan := trace.SpanFromContext(ctx)
-	log.Printf("🔧 SDK ScrapeAndWait: Incoming context span valid: %v", incomingSpan.SpanContext().IsValid())
-	if incomingSpan.SpanContext().IsValid() {
-		log.Printf("🔧 SDK ScrapeAndWait: Incoming trace ID: %s", incomingSpan.SpanContext().TraceID().String())
-	}
+	c.logSpanContext(ctx, "ScrapeAndWait: incoming context", trace.SpanFromContext(ctx))
 
 	// Create a span for this operation
 	// If there's no existing span in context, this creates a new root span
 	ctx, span := c.tracer.Start(ctx, "scrapeapi.ScrapeAndWait")
 	defer span.End()
-	
-	log.Printf("🔧 SDK ScrapeAndWait: Created span trace ID: %s", span.SpanContext().TraceID().String())
 
-	cfg := &waitConfig{
-		pollInterval: 2 * time.Second, // default
-	}
+	c.logSpanContext(ctx, "ScrapeAndWait: created span", span)
+
+	cfg := &waitConfig{}
 
 	for _, opt := range opts {
 		opt(cfg)
 	}
 
-	startResp, err := c.StartScrape(ctx, req)
+	startResp, err := c.StartScrape(ctx, req, cfg.requestOpts...)
 	if err != nil {
 		return nil, fmt.Errorf("start scrape: %w", err)
 	}
 
-	return c.WaitForCompletion(ctx, startResp.RequestID, cfg.pollInterval)
+	span.SetAttributes(attrRequestID.String(startResp.RequestID))
+
+	resp, err := c.WaitForCompletion(ctx, startResp.RequestID, cfg.pollInterval, opts...)
+	if resp != nil {
+		recordResponse(span, resp)
+	}
+	if err != nil {
+		return resp, err
+	}
+
+	return resp, cfg.checkResult(resp, req)
+}
+
+// checkResult validates the result of a completed job when WithResultValidation was given
+func (cfg *waitConfig) checkResult(resp *ScrapeResponse, req *ScrapeRequest) error {
+	if !cfg.validateResult || req.OutputSchema == nil || !req.OutputFormat.isJSON() {
+		return nil
+	}
+	checked := resp
+	if req.IncludeConfidence {
+		plain := *resp
+		plain.Result = stripConfidence(resultData(resp.Result))
+		checked = &plain
+	}
+	return ValidateResult(checked, req.OutputSchema)
+}
+
+// ApplyWaitOptions applies opts to a job that is already finished, for
+// ScrapeClient fakes that do not poll. It calls the WithProgress callback with
+// resp and, when req is not nil and the job completed, checks the result as
+// WithResultValidation makes ScrapeAndWait do.
+func ApplyWaitOptions(resp *ScrapeResponse, req *ScrapeRequest, opts ...WaitOption) error {
+	cfg := &waitConfig{}
+	for _, opt := range opts {
+		opt(cfg)
+	}
+	cfg.reportProgress(resp)
+	if req == nil || resp.Status != StatusCompleted {
+		return nil
+	}
+	return cfg.checkResult(resp, req)
 }
 
 // Helper functions for pointer types
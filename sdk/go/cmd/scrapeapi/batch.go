@@ -0,0 +1,245 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	scrapeapi "github.com/dir01/scrapeapi/sdk/go"
+	"github.com/dir01/scrapeapi/sdk/go/pipeline"
+)
+
+func init() {
+	register(&command{
+		name:    "batch",
+		usage:   "--urls FILE --prompt PROMPT (--out DIR | --ndjson FILE) [--schema FILE] [--concurrency N]",
+		summary: "Scrape every URL in a file, writing one result file per URL or an NDJSON file",
+		run:     runBatch,
+	})
+}
+
+func runBatch(ctx context.Context, fs *flag.FlagSet, args []string) error {
+	cf := addClientFlags(fs)
+	jf := addJobFlags(fs)
+	urlsFile := fs.String("urls", "", "file with one URL per line; blank lines and lines starting with # are skipped")
+	outDir := fs.String("out", "", "directory to write the results to")
+	ndjson := fs.String("ndjson", "", "file to write every result to as one JSON record per line")
+	concurrency := fs.Int("concurrency", 5, "how many jobs to run at once")
+	retries := fs.Int("retries", 2, "how often to retry a failed job")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() > 0 || *urlsFile == "" || (*outDir == "" && *ndjson == "") || *concurrency < 1 {
+		return errUsage
+	}
+	if err := checkOutputFormat(*jf.output); err != nil {
+		return err
+	}
+
+	p, err := cf.profile()
+	if err != nil {
+		return err
+	}
+	jf.applyProfile(fs, p)
+	client, err := cf.client()
+	if err != nil {
+		return err
+	}
+
+	urls, err := readURLs(*urlsFile)
+	if err != nil {
+		return err
+	}
+	// Read the schema once up front, also to fail early on a bad schema file
+	probe, err := jf.request("")
+	if err != nil {
+		return err
+	}
+	if *outDir != "" {
+		if err := os.MkdirAll(*outDir, 0o755); err != nil {
+			return err
+		}
+	}
+
+	b := &batchRun{
+		client:  client,
+		jobs:    jf,
+		outDir:  *outDir,
+		retries: *retries,
+		names:   resultNames(urls),
+		schema:  probe.OutputSchema,
+	}
+	if *ndjson != "" {
+		if b.records, err = pipeline.CreateNDJSONFile(*ndjson); err != nil {
+			return err
+		}
+	}
+	err = b.run(ctx, urls, *concurrency)
+	if b.records != nil {
+		if cerr := b.records.Close(ctx); err == nil {
+			err = cerr
+		}
+	}
+	return err
+}
+
+type batchRun struct {
+	client  *scrapeapi.Client
+	jobs    *jobFlags
+	outDir  string
+	retries int
+	names   map[string]string
+	schema  interface{}
+	// records, if set, gets every result besides or instead of outDir
+	records *pipeline.NDJSONSink
+
+	mu     sync.Mutex
+	failed int
+}
+
+func (b *batchRun) run(ctx context.Context, urls []string, concurrency int) error {
+	sem := make(chan struct{}, concurrency)
+	var wg sync.WaitGroup
+	for _, u := range urls {
+		select {
+		case sem <- struct{}{}:
+		case <-ctx.Done():
+			wg.Wait()
+			return ctx.Err()
+		}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			defer func() { <-sem }()
+			b.scrape(ctx, u)
+		}()
+	}
+	wg.Wait()
+
+	fmt.Fprintf(os.Stderr, "%d succeeded, %d failed\n", len(urls)-b.failed, b.failed)
+	if b.failed > 0 {
+		return fmt.Errorf("%d of %d URLs failed", b.failed, len(urls))
+	}
+	return ctx.Err()
+}
+
+// scrape runs the job for u, retrying failures, and writes its result or error
+func (b *batchRun) scrape(ctx context.Context, u string) {
+	name := b.names[u]
+	file := name + "." + outputExt(*b.jobs.output)
+	resp, err := b.scrapeWithRetries(ctx, u)
+	if err == nil && b.outDir != "" {
+		err = b.writeResultFile(filepath.Join(b.outDir, file), resp)
+	}
+	if err == nil && b.records != nil {
+		err = b.records.Write(ctx, pipeline.NewItem(scrapeapi.PoolResult{Response: resp}))
+	}
+	if err != nil {
+		b.mu.Lock()
+		b.failed++
+		b.mu.Unlock()
+		fmt.Fprintf(os.Stderr, "FAIL %s: %v\n", u, err)
+		if b.outDir != "" {
+			_ = os.WriteFile(filepath.Join(b.outDir, name+".error.txt"), []byte(err.Error()+"\n"), 0o644)
+		}
+		return
+	}
+	if b.outDir == "" {
+		fmt.Fprintf(os.Stderr, "ok   %s\n", u)
+		return
+	}
+	fmt.Fprintf(os.Stderr, "ok   %s -> %s\n", u, file)
+}
+
+func (b *batchRun) scrapeWithRetries(ctx context.Context, u string) (*scrapeapi.ScrapeResponse, error) {
+	req, err := b.jobs.request(u)
+	if err != nil {
+		return nil, err
+	}
+	for attempt := 0; ; attempt++ {
+		resp, err := b.client.ScrapeAndWait(ctx, req, b.jobs.waitOptions()...)
+		if err == nil || attempt >= b.retries || !retryableJobError(err) || ctx.Err() != nil {
+			return resp, err
+		}
+		fmt.Fprintf(os.Stderr, "retry %s (attempt %d): %v\n", u, attempt+2, err)
+		select {
+		case <-time.After(time.Duration(attempt+1) * 2 * time.Second):
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+}
+
+// retryableJobError reports whether a failed job is worth running again;
+// invalid requests fail the same way every time
+func retryableJobError(err error) bool {
+	var verr *scrapeapi.ValidationError
+	if errors.As(err, &verr) {
+		return false
+	}
+	var apiErr *scrapeapi.APIError
+	if errors.As(err, &apiErr) && apiErr.StatusCode >= 400 && apiErr.StatusCode < 500 && apiErr.StatusCode != 429 {
+		return false
+	}
+	return !errors.Is(err, context.Canceled)
+}
+
+// readURLs reads one URL per line, skipping blank lines and # comments
+func readURLs(path string) ([]string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var urls []string
+	seen := map[string]bool{}
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") || seen[line] {
+			continue
+		}
+		seen[line] = true
+		urls = append(urls, line)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("read %s: %w", path, err)
+	}
+	return urls, nil
+}
+
+// resultNames derives a file name from every URL with pipeline.Slug,
+// numbering names that collide
+func resultNames(urls []string) map[string]string {
+	names := map[string]string{}
+	taken := map[string]int{}
+	for _, raw := range urls {
+		name := pipeline.Slug(raw)
+		taken[name]++
+		if n := taken[name]; n > 1 {
+			name = fmt.Sprintf("%s-%d", name, n)
+		}
+		names[raw] = name
+	}
+	return names
+}
+
+func (b *batchRun) writeResultFile(path string, resp *scrapeapi.ScrapeResponse) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	err = writeResult(f, resp, *b.jobs.output, b.schema)
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	return err
+}
@@ -0,0 +1,209 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"strings"
+
+	scrapeapi "github.com/dir01/scrapeapi/sdk/go"
+)
+
+func init() {
+	register(&command{
+		name:    "completion",
+		usage:   "bash|zsh|fish",
+		summary: "Print a shell completion script",
+		run:     runCompletion,
+	})
+	register(&command{
+		name:   "__complete",
+		usage:  "WORD...",
+		hidden: true,
+		run:    runComplete,
+	})
+}
+
+// completionScripts call "scrapeapi __complete" with the words typed so far
+// and fall back to file names when it has no candidates
+var completionScripts = map[string]string{
+	"bash": `# bash completion for scrapeapi
+# Add to ~/.bashrc: source <(scrapeapi completion bash)
+_scrapeapi() {
+	local IFS=$'\n'
+	COMPREPLY=($(scrapeapi __complete "${COMP_WORDS[@]:1:COMP_CWORD}" 2>/dev/null))
+}
+complete -o default -F _scrapeapi scrapeapi
+`,
+	"zsh": `#compdef scrapeapi
+# zsh completion for scrapeapi
+# Add to ~/.zshrc after compinit: source <(scrapeapi completion zsh)
+_scrapeapi() {
+	local -a candidates
+	candidates=("${(@f)$(scrapeapi __complete "${(@)words[2,CURRENT]}" 2>/dev/null)}")
+	if [[ -n ${candidates[1]} ]]; then
+		compadd -a candidates
+	else
+		_files
+	fi
+}
+if [[ $funcstack[1] == _scrapeapi ]]; then
+	_scrapeapi "$@"
+else
+	compdef _scrapeapi scrapeapi
+fi
+`,
+	"fish": `# fish completion for scrapeapi
+# Save as ~/.config/fish/completions/scrapeapi.fish: scrapeapi completion fish > ~/.config/fish/completions/scrapeapi.fish
+function __scrapeapi_complete
+	set -l cur (commandline -ct)
+	set -l candidates (scrapeapi __complete (commandline -opc)[2..-1] "$cur" 2>/dev/null)
+	if test (count $candidates) -gt 0
+		printf '%s\n' $candidates
+	else
+		__fish_complete_path "$cur"
+	end
+end
+complete -c scrapeapi -f -a '(__scrapeapi_complete)'
+`,
+}
+
+func runCompletion(ctx context.Context, fs *flag.FlagSet, args []string) error {
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	script, ok := completionScripts[fs.Arg(0)]
+	if fs.NArg() != 1 || !ok {
+		return errUsage
+	}
+	_, err := io.WriteString(os.Stdout, script)
+	return err
+}
+
+func runComplete(ctx context.Context, fs *flag.FlagSet, args []string) error {
+	for _, c := range completions(args) {
+		fmt.Println(c)
+	}
+	return nil
+}
+
+// completions returns the candidates for the last of words, which are the
+// command line after "scrapeapi" up to and including the word being typed
+func completions(words []string) []string {
+	if len(words) == 0 {
+		words = []string{""}
+	}
+	cur := words[len(words)-1]
+	if len(words) == 1 {
+		names := []string{"help"}
+		for _, c := range commands {
+			if !c.hidden {
+				names = append(names, c.name)
+			}
+		}
+		return withPrefix(names, cur)
+	}
+
+	c := findCommand(words[0])
+	if c == nil {
+		return nil
+	}
+	fs := commandFlags(c)
+	prev := strings.TrimLeft(words[len(words)-2], "-")
+	if f := fs.Lookup(prev); f != nil && !isBoolFlag(f) && strings.HasPrefix(words[len(words)-2], "-") {
+		return withPrefix(flagValues(f.Name), cur)
+	}
+	if strings.HasPrefix(cur, "-") {
+		var names []string
+		fs.VisitAll(func(f *flag.Flag) {
+			names = append(names, "--"+f.Name)
+		})
+		return withPrefix(names, cur)
+	}
+
+	switch {
+	case len(c.subcommands) > 0 && len(words) == 2:
+		return withPrefix(c.subcommands, cur)
+	case c.jobArgs:
+		var ids []string
+		for _, id := range loadRecentIDs() {
+			// Don't offer an ID already on the command line
+			if !slices.Contains(words[1:len(words)-1], id) {
+				ids = append(ids, id)
+			}
+		}
+		return withPrefix(ids, cur)
+	}
+	return nil
+}
+
+func findCommand(name string) *command {
+	for _, c := range commands {
+		if c.name == name && !c.hidden {
+			return c
+		}
+	}
+	return nil
+}
+
+// commandFlags returns c's flags by running it with -h, which defines them
+// and returns before doing anything else
+func commandFlags(c *command) *flag.FlagSet {
+	fs := newFlagSet(c)
+	fs.SetOutput(io.Discard)
+	var args []string
+	if len(c.subcommands) > 0 {
+		args = append(args, c.subcommands[0])
+	}
+	c.run(context.Background(), fs, append(args, "-h"))
+	return fs
+}
+
+func isBoolFlag(f *flag.Flag) bool {
+	b, ok := f.Value.(interface{ IsBoolFlag() bool })
+	return ok && b.IsBoolFlag()
+}
+
+// flagValues returns the values offered for a flag; nil means file names
+func flagValues(name string) []string {
+	switch name {
+	case "output":
+		return outputFormats
+	case "status":
+		return []string{
+			string(scrapeapi.StatusQueued), string(scrapeapi.StatusRunning), string(scrapeapi.StatusCompleted),
+			string(scrapeapi.StatusFailed), string(scrapeapi.StatusCanceled),
+		}
+	case "graph":
+		return []string{
+			string(scrapeapi.GraphSmart), string(scrapeapi.GraphMulti), string(scrapeapi.GraphSearch),
+			string(scrapeapi.GraphSitemap), string(scrapeapi.GraphCrawl), string(scrapeapi.GraphFeed),
+		}
+	case "level":
+		return []string{
+			string(scrapeapi.LogLevelDebug), string(scrapeapi.LogLevelInfo),
+			string(scrapeapi.LogLevelWarn), string(scrapeapi.LogLevelError),
+		}
+	case "profile":
+		path := os.Getenv("SCRAPEAPI_CONFIG")
+		cfg, err := loadConfig(firstNonEmpty(path, defaultConfigPath()), path != "")
+		if err != nil {
+			return nil
+		}
+		return cfg.profileNames()
+	}
+	return nil
+}
+
+func withPrefix(candidates []string, prefix string) []string {
+	var matched []string
+	for _, c := range candidates {
+		if strings.HasPrefix(c, prefix) {
+			matched = append(matched, c)
+		}
+	}
+	return matched
+}
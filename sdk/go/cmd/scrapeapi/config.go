@@ -0,0 +1,117 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// config is the CLI config file, by default ~/.config/scrapeapi/config.yaml:
+//
+//	default_profile: staging
+//	profiles:
+//	  staging:
+//	    base_url: https://scrapeapi.staging.example.com
+//	    api_key_env: SCRAPEAPI_STAGING_KEY
+//	    model: openai/gpt-4o-mini
+//	    timeout: 5m
+//	  prod:
+//	    base_url: https://scrapeapi.example.com
+//	    api_key: sk-...
+type config struct {
+	DefaultProfile string              `yaml:"default_profile"`
+	Profiles       map[string]*profile `yaml:"profiles"`
+}
+
+// profile holds the settings of one deployment
+type profile struct {
+	BaseURL string `yaml:"base_url"`
+	APIKey  string `yaml:"api_key"`
+	// APIKeyEnv names an environment variable holding the API key, to keep it out of the file
+	APIKeyEnv string `yaml:"api_key_env"`
+	// Model is the default LLM model of jobs started with this profile
+	Model string `yaml:"model"`
+	// Timeout is how long to wait for jobs by default
+	Timeout time.Duration `yaml:"timeout"`
+}
+
+func (p *profile) apiKey() string {
+	if p.APIKeyEnv != "" {
+		if key := os.Getenv(p.APIKeyEnv); key != "" {
+			return key
+		}
+	}
+	return p.APIKey
+}
+
+// defaultConfigPath is $XDG_CONFIG_HOME/scrapeapi/config.yaml, falling back to ~/.config
+func defaultConfigPath() string {
+	dir := os.Getenv("XDG_CONFIG_HOME")
+	if dir == "" {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return ""
+		}
+		dir = filepath.Join(home, ".config")
+	}
+	return filepath.Join(dir, "scrapeapi", "config.yaml")
+}
+
+// loadConfig reads the config file at path; a missing file is an empty config
+// unless it was asked for explicitly
+func loadConfig(path string, explicit bool) (*config, error) {
+	cfg := &config{}
+	if path == "" {
+		return cfg, nil
+	}
+	raw, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) && !explicit {
+		return cfg, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("read config: %w", err)
+	}
+	if err := yaml.Unmarshal(raw, cfg); err != nil {
+		return nil, fmt.Errorf("parse config %s: %w", path, err)
+	}
+	return cfg, nil
+}
+
+// profile returns the named profile, the default profile when name is
+// empty, or an empty profile when there is no default
+func (c *config) profile(name string) (*profile, error) {
+	explicit := name != ""
+	if name == "" {
+		name = c.DefaultProfile
+	}
+	if name == "" {
+		return &profile{}, nil
+	}
+	p, ok := c.Profiles[name]
+	if !ok || p == nil {
+		if !explicit {
+			return nil, fmt.Errorf("default_profile %q is not defined", name)
+		}
+		return nil, fmt.Errorf("unknown profile %q, have %s", name, strings.Join(c.profileNames(), ", "))
+	}
+	return p, nil
+}
+
+func (c *config) profileNames() []string {
+	names := make([]string, 0, len(c.Profiles))
+	for name := range c.Profiles {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	if len(names) == 0 {
+		return []string{"none"}
+	}
+	return names
+}
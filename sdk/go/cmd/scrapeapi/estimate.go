@@ -0,0 +1,128 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+)
+
+func init() {
+	register(&command{
+		name:    "estimate",
+		usage:   "--url URL --prompt PROMPT [--schema FILE] [--models MODEL,...]",
+		summary: "Estimate a job's token usage and cost per model without running it",
+		run:     runEstimate,
+	})
+}
+
+// estimate is the server's estimate of a job run with one model
+type estimate struct {
+	Model            string   `json:"model"`
+	EstimatedTokens  *int     `json:"estimated_tokens"`
+	EstimatedCostUSD *float64 `json:"estimated_cost_usd"`
+	Problems         []string `json:"problems,omitempty"`
+}
+
+func runEstimate(ctx context.Context, fs *flag.FlagSet, args []string) error {
+	cf := addClientFlags(fs)
+	jf := addRequestFlags(fs)
+	models := fs.String("models", "", "comma-separated models to compare, e.g. openai/gpt-4o-mini,openai/gpt-4o (default: --model)")
+	output := fs.String("output", "table", "format: table or json")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() > 0 {
+		return errUsage
+	}
+	if *output != "table" && *output != "json" {
+		return fmt.Errorf("unknown output format %q, want table or json", *output)
+	}
+
+	p, err := cf.profile()
+	if err != nil {
+		return err
+	}
+	jf.applyProfile(fs, p)
+	client, err := cf.client()
+	if err != nil {
+		return err
+	}
+
+	// An empty model leaves the choice to the server
+	names := []string{jf.model}
+	if *models != "" {
+		names = nil
+		for _, m := range strings.Split(*models, ",") {
+			if m = strings.TrimSpace(m); m != "" {
+				names = append(names, m)
+			}
+		}
+	}
+
+	var estimates []*estimate
+	local, invalid := false, false
+	for _, model := range names {
+		jf.model = model
+		req, err := jf.request("")
+		if err != nil {
+			return err
+		}
+		result, err := client.DryRun(ctx, req)
+		if err != nil {
+			return fmt.Errorf("%s: %w", modelLabel(model), err)
+		}
+
+		e := &estimate{Model: model, EstimatedTokens: result.EstimatedTokens, EstimatedCostUSD: result.EstimatedCostUSD}
+		if e.Model == "" && result.Config.LLM != nil {
+			e.Model = result.Config.LLM.Model
+		}
+		for _, problem := range result.Problems {
+			e.Problems = append(e.Problems, problem.Error())
+		}
+		estimates = append(estimates, e)
+		local = local || result.Local
+		invalid = invalid || !result.Valid()
+	}
+
+	if *output == "json" {
+		err = writeJSON(os.Stdout, estimates)
+	} else {
+		err = writeTable(os.Stdout, []string{"model", "tokens", "cost_usd", "problems"}, estimateRows(estimates))
+	}
+	if err != nil {
+		return err
+	}
+
+	if local {
+		fmt.Fprintln(os.Stderr, "the server has no validate endpoint, so only the request was checked and there is no estimate")
+	}
+	if invalid {
+		return fmt.Errorf("the request has problems; see above")
+	}
+	return nil
+}
+
+func estimateRows(estimates []*estimate) [][]string {
+	rows := make([][]string, len(estimates))
+	for i, e := range estimates {
+		tokens, cost := "-", "-"
+		if e.EstimatedTokens != nil {
+			tokens = strconv.Itoa(*e.EstimatedTokens)
+		}
+		if e.EstimatedCostUSD != nil {
+			cost = strconv.FormatFloat(*e.EstimatedCostUSD, 'f', 4, 64)
+		}
+		rows[i] = []string{modelLabel(e.Model), tokens, cost, strings.Join(e.Problems, "; ")}
+	}
+	return rows
+}
+
+func modelLabel(model string) string {
+	if model == "" {
+		return "(server default)"
+	}
+	return model
+}
@@ -0,0 +1,155 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	scrapeapi "github.com/dir01/scrapeapi/sdk/go"
+)
+
+func init() {
+	register(&command{
+		name:    "get",
+		usage:   "REQUEST_ID",
+		jobArgs: true,
+		summary: "Print the current state of a job",
+		run:     runGet,
+	})
+	register(&command{
+		name:    "watch",
+		usage:   "[--poll-interval D] REQUEST_ID",
+		jobArgs: true,
+		summary: "Follow a job until it finishes, then print its result",
+		run:     runWatch,
+	})
+}
+
+func runGet(ctx context.Context, fs *flag.FlagSet, args []string) error {
+	cf := addClientFlags(fs)
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() != 1 {
+		return errUsage
+	}
+
+	client, err := cf.client()
+	if err != nil {
+		return err
+	}
+	resp, err := client.GetScrape(ctx, fs.Arg(0))
+	if err != nil {
+		return err
+	}
+	recent.add(resp.RequestID)
+	return writeJSON(os.Stdout, resp)
+}
+
+func runWatch(ctx context.Context, fs *flag.FlagSet, args []string) error {
+	cf := addClientFlags(fs)
+	pollEvery := fs.Duration("poll-interval", 2*time.Second, "how often to poll the job")
+	timeout := fs.Duration("timeout", 0, "give up after this long (default: wait until the job finishes)")
+	output := addOutputFlag(fs)
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() != 1 {
+		return errUsage
+	}
+	if err := checkOutputFormat(*output); err != nil {
+		return err
+	}
+
+	client, err := cf.client()
+	if err != nil {
+		return err
+	}
+
+	progress := newProgressLine(os.Stderr)
+	opts := []scrapeapi.WaitOption{scrapeapi.WithProgress(progress.update)}
+	if *timeout > 0 {
+		opts = append(opts, scrapeapi.WithMaxWait(*timeout))
+	}
+	resp, err := client.WaitForCompletion(ctx, fs.Arg(0), *pollEvery, opts...)
+	progress.done()
+	if err != nil {
+		return err
+	}
+	return printResult(resp, *output, nil)
+}
+
+// progressLine shows the state of a job on one line, rewritten in place on
+// a terminal and appended as a new line per change otherwise
+type progressLine struct {
+	w        io.Writer
+	terminal bool
+	started  time.Time
+	last     string
+}
+
+func newProgressLine(f *os.File) *progressLine {
+	return &progressLine{w: f, terminal: isTerminal(f), started: time.Now()}
+}
+
+func (p *progressLine) update(resp *scrapeapi.ScrapeResponse) {
+	line := describeJob(resp)
+	if p.terminal {
+		fmt.Fprintf(p.w, "\r\033[K%s  %s", line, time.Since(p.started).Round(time.Second))
+		return
+	}
+	// Without a terminal, only changes are worth a line
+	if line != p.last {
+		fmt.Fprintln(p.w, line)
+	}
+	p.last = line
+}
+
+func (p *progressLine) done() {
+	if p.terminal {
+		fmt.Fprintln(p.w)
+	}
+}
+
+// describeJob summarizes a job's status and progress, e.g.
+// "job-1 running extracting 40% 4/10 pages"
+func describeJob(resp *scrapeapi.ScrapeResponse) string {
+	parts := []string{resp.RequestID, string(resp.Status)}
+	if p := resp.Progress; p != nil && resp.Status.IsPending() {
+		if p.Stage != "" {
+			parts = append(parts, string(p.Stage))
+		}
+		if p.Percent > 0 {
+			parts = append(parts, fmt.Sprintf("%.0f%%", p.Percent))
+		}
+		if p.PagesTotal > 0 {
+			parts = append(parts, fmt.Sprintf("%d/%d pages", p.PagesDone, p.PagesTotal))
+		}
+	}
+	return strings.Join(parts, " ")
+}
+
+func isTerminal(f *os.File) bool {
+	info, err := f.Stat()
+	return err == nil && info.Mode()&os.ModeCharDevice != 0
+}
+
+// writeJSON writes v to w as indented JSON
+func writeJSON(w io.Writer, v interface{}) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	enc.SetEscapeHTML(false)
+	return enc.Encode(v)
+}
+
+// writeCompactJSON writes v to w as one line of JSON
+func writeCompactJSON(w io.Writer, v interface{}) error {
+	enc := json.NewEncoder(w)
+	enc.SetEscapeHTML(false)
+	return enc.Encode(v)
+}
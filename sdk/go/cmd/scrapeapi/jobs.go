@@ -0,0 +1,190 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	scrapeapi "github.com/dir01/scrapeapi/sdk/go"
+)
+
+func init() {
+	register(&command{
+		name:    "list",
+		usage:   "[--status STATUS] [--since DURATION] [--graph GRAPH] [--tag TAG] [--limit N]",
+		summary: "List jobs, most recent first",
+		run:     runList,
+	})
+	register(&command{
+		name:    "cancel",
+		usage:   "REQUEST_ID...",
+		jobArgs: true,
+		summary: "Stop queued or running jobs",
+		run:     runCancel,
+	})
+	register(&command{
+		name:    "delete",
+		usage:   "REQUEST_ID...",
+		jobArgs: true,
+		summary: "Delete jobs and their results",
+		run:     runDelete,
+	})
+}
+
+func runList(ctx context.Context, fs *flag.FlagSet, args []string) error {
+	cf := addClientFlags(fs)
+	status := fs.String("status", "", "only jobs in this status: queued, running, completed, failed or canceled")
+	since := fs.Duration("since", 0, "only jobs created within this long, e.g. 1h")
+	graph := fs.String("graph", "", "only jobs that ran this graph")
+	tag := fs.String("tag", "", "only jobs carrying this tag")
+	limit := fs.Int("limit", 50, "list at most this many jobs; 0 lists all")
+	output := fs.String("output", "table", "format: table, json or ndjson")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() > 0 {
+		return errUsage
+	}
+
+	opts := scrapeapi.ListOptions{Status: scrapeapi.Status(*status), Graph: scrapeapi.Graph(*graph), Tag: *tag}
+	if opts.Status != "" && !opts.Status.IsValid() {
+		return fmt.Errorf("unknown status %q", *status)
+	}
+	if *since > 0 {
+		opts.CreatedAfter = time.Now().Add(-*since)
+	}
+	if *limit > 0 {
+		opts.Limit = *limit
+	}
+
+	client, err := cf.client()
+	if err != nil {
+		return err
+	}
+	var jobs []*scrapeapi.ScrapeResponse
+	for job, err := range client.AllScrapes(ctx, opts) {
+		if err != nil {
+			return err
+		}
+		jobs = append(jobs, job)
+		if *limit > 0 && len(jobs) >= *limit {
+			break
+		}
+	}
+
+	// Oldest first, so the newest job ends up most recent
+	for i := len(jobs) - 1; i >= 0; i-- {
+		recent.add(jobs[i].RequestID)
+	}
+
+	switch *output {
+	case "json":
+		return writeJSON(os.Stdout, jobs)
+	case "ndjson":
+		for _, job := range jobs {
+			if err := writeCompactJSON(os.Stdout, job); err != nil {
+				return err
+			}
+		}
+		return nil
+	case "table":
+		return writeTable(os.Stdout, jobColumns, jobRows(jobs))
+	}
+	return fmt.Errorf("unknown output format %q, want table, json or ndjson", *output)
+}
+
+var jobColumns = []string{"request_id", "status", "graph", "created", "source"}
+
+func jobRows(jobs []*scrapeapi.ScrapeResponse) [][]string {
+	rows := make([][]string, len(jobs))
+	for i, job := range jobs {
+		created := ""
+		if job.CreatedAt != nil {
+			created = job.CreatedAt.Local().Format(time.DateTime)
+		}
+		rows[i] = []string{job.RequestID, string(job.Status), string(job.Graph), created, jobSource(job)}
+	}
+	return rows
+}
+
+// jobSource is the URL a job scraped, or a count of its sources
+func jobSource(job *scrapeapi.ScrapeResponse) string {
+	switch {
+	case job.WebsiteURL != nil:
+		return *job.WebsiteURL
+	case len(job.Sources) == 1:
+		return job.Sources[0].URL
+	case len(job.Sources) > 1:
+		return fmt.Sprintf("%d sources", len(job.Sources))
+	}
+	return ""
+}
+
+func runCancel(ctx context.Context, fs *flag.FlagSet, args []string) error {
+	cf := addClientFlags(fs)
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() == 0 {
+		return errUsage
+	}
+	client, err := cf.client()
+	if err != nil {
+		return err
+	}
+
+	return forEachJob(fs.Args(), func(id string) error {
+		resp, err := client.CancelScrape(ctx, id)
+		if err != nil {
+			return err
+		}
+		if resp.Status == scrapeapi.StatusCanceled {
+			fmt.Printf("%s canceled\n", id)
+		} else {
+			fmt.Printf("%s already %s\n", id, resp.Status)
+		}
+		return nil
+	})
+}
+
+func runDelete(ctx context.Context, fs *flag.FlagSet, args []string) error {
+	cf := addClientFlags(fs)
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() == 0 {
+		return errUsage
+	}
+	client, err := cf.client()
+	if err != nil {
+		return err
+	}
+
+	return forEachJob(fs.Args(), func(id string) error {
+		if err := client.DeleteScrape(ctx, id); err != nil {
+			return err
+		}
+		recent.remove(id)
+		fmt.Printf("%s deleted\n", id)
+		return nil
+	})
+}
+
+// forEachJob runs fn for every request ID, reporting failures and carrying
+// on with the rest
+func forEachJob(ids []string, fn func(id string) error) error {
+	var failed []string
+	for _, id := range ids {
+		if err := fn(id); err != nil {
+			fmt.Fprintf(os.Stderr, "%s: %v\n", id, err)
+			failed = append(failed, id)
+		}
+	}
+	if len(failed) > 0 {
+		return fmt.Errorf("failed for %s", strings.Join(failed, ", "))
+	}
+	return nil
+}
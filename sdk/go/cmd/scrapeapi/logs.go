@@ -0,0 +1,136 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+
+	scrapeapi "github.com/dir01/scrapeapi/sdk/go"
+)
+
+func init() {
+	register(&command{
+		name:    "logs",
+		usage:   "[-f] [--tail N] [--level LEVEL] REQUEST_ID",
+		summary: "Print a job's server-side log",
+		jobArgs: true,
+		run:     runLogs,
+	})
+}
+
+func runLogs(ctx context.Context, fs *flag.FlagSet, args []string) error {
+	cf := addClientFlags(fs)
+	follow := fs.Bool("f", false, "keep printing new entries until the job finishes")
+	tail := fs.Int("tail", 0, "start with only the last N entries")
+	level := fs.String("level", "", "only entries at or above this level: debug, info, warning or error")
+	output := fs.String("output", "text", "format: text or ndjson")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() != 1 {
+		return errUsage
+	}
+	if *output != "text" && *output != "ndjson" {
+		return fmt.Errorf("unknown output format %q, want text or ndjson", *output)
+	}
+	switch scrapeapi.LogLevel(*level) {
+	case "", scrapeapi.LogLevelDebug, scrapeapi.LogLevelInfo, scrapeapi.LogLevelWarn, scrapeapi.LogLevelError:
+	default:
+		return fmt.Errorf("unknown level %q, want debug, info, warning or error", *level)
+	}
+
+	client, err := cf.client()
+	if err != nil {
+		return err
+	}
+	id := fs.Arg(0)
+	opts := scrapeapi.LogOptions{Tail: *tail, MinLevel: scrapeapi.LogLevel(*level)}
+	printEntry := func(entry scrapeapi.LogEntry) error {
+		if *output == "ndjson" {
+			return writeCompactJSON(os.Stdout, entry)
+		}
+		_, err := io.WriteString(os.Stdout, formatLogEntry(entry, isTerminal(os.Stdout)))
+		return err
+	}
+
+	if !*follow {
+		logs, err := client.GetScrapeLogs(ctx, id, opts)
+		if err != nil {
+			return err
+		}
+		recent.add(id)
+		for _, entry := range logs.Entries {
+			if err := printEntry(entry); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	recent.add(id)
+	for entry, err := range client.FollowScrapeLogs(ctx, id, opts) {
+		if err != nil {
+			return err
+		}
+		if err := printEntry(entry); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// logLevelColors are the ANSI colors of the levels that need attention
+var logLevelColors = map[scrapeapi.LogLevel]string{
+	scrapeapi.LogLevelDebug: "\033[2m",
+	scrapeapi.LogLevelWarn:  "\033[33m",
+	scrapeapi.LogLevelError: "\033[31m",
+}
+
+// formatLogEntry renders an entry as one line: time, level, message and
+// its fields as key=value in name order, colored by level when color is set
+func formatLogEntry(entry scrapeapi.LogEntry, color bool) string {
+	var b strings.Builder
+	b.WriteString(entry.Timestamp.Local().Format("15:04:05.000"))
+	fmt.Fprintf(&b, " %-5s %s", strings.ToUpper(shortLevel(entry.Level)), entry.Message)
+
+	keys := make([]string, 0, len(entry.Fields))
+	for k := range entry.Fields {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		fmt.Fprintf(&b, " %s=%s", k, formatLogValue(entry.Fields[k]))
+	}
+
+	line := b.String()
+	if c, ok := logLevelColors[entry.Level]; ok && color {
+		line = c + line + "\033[0m"
+	}
+	return line + "\n"
+}
+
+func shortLevel(level scrapeapi.LogLevel) string {
+	if level == scrapeapi.LogLevelWarn {
+		return "warn"
+	}
+	return string(level)
+}
+
+// formatLogValue quotes values holding spaces, so lines stay parseable
+func formatLogValue(v interface{}) string {
+	var s string
+	switch val := v.(type) {
+	case string:
+		s = val
+	default:
+		s = fmt.Sprint(val)
+	}
+	if s == "" || strings.ContainsAny(s, " \t\n\"=") {
+		return fmt.Sprintf("%q", s)
+	}
+	return s
+}
@@ -0,0 +1,180 @@
+// Command scrapeapi runs and inspects ScrapeAPI jobs from the terminal.
+//
+//	scrapeapi scrape --url https://example.com/jobs --prompt "Extract the job ads" --schema schema.json
+//
+// The server is taken from --base-url, SCRAPEAPI_BASE_URL or a profile in
+// ~/.config/scrapeapi/config.yaml selected with --profile, and an API key
+// likewise from --api-key, SCRAPEAPI_API_KEY or the profile.
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"strings"
+
+	scrapeapi "github.com/dir01/scrapeapi/sdk/go"
+)
+
+// defaultBaseURL is where docker-compose exposes the API
+const defaultBaseURL = "http://127.0.0.1:8080"
+
+// command is one scrapeapi subcommand
+type command struct {
+	name    string
+	usage   string
+	summary string
+	// subcommands are the words the first argument can be, for completion
+	subcommands []string
+	// jobArgs is set when the arguments are request IDs, which completion
+	// offers from the IDs recently seen
+	jobArgs bool
+	// hidden commands are left out of the usage
+	hidden bool
+	// run executes the command; fs is a flag set for it with no flags defined yet
+	run func(ctx context.Context, fs *flag.FlagSet, args []string) error
+}
+
+var commands []*command
+
+func register(c *command) {
+	commands = append(commands, c)
+}
+
+// errUsage makes main print the command's usage instead of an error message
+var errUsage = errors.New("usage")
+
+func main() {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	if len(os.Args) < 2 || os.Args[1] == "help" || os.Args[1] == "-h" || os.Args[1] == "--help" {
+		printUsage(os.Stderr)
+		os.Exit(2)
+	}
+
+	name := os.Args[1]
+	for _, c := range commands {
+		if c.name != name {
+			continue
+		}
+		err := c.run(ctx, newFlagSet(c), os.Args[2:])
+		recent.save()
+		switch {
+		case err == nil:
+			return
+		case errors.Is(err, flag.ErrHelp):
+			os.Exit(0)
+		case errors.Is(err, errUsage):
+			fmt.Fprintf(os.Stderr, "usage: scrapeapi %s %s\n", c.name, c.usage)
+			os.Exit(2)
+		default:
+			fmt.Fprintf(os.Stderr, "scrapeapi %s: %v\n", c.name, err)
+			os.Exit(1)
+		}
+	}
+
+	fmt.Fprintf(os.Stderr, "scrapeapi: unknown command %q\n\n", name)
+	printUsage(os.Stderr)
+	os.Exit(2)
+}
+
+func printUsage(w io.Writer) {
+	fmt.Fprintln(w, "usage: scrapeapi <command> [flags]")
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "commands:")
+	for _, c := range commands {
+		if c.hidden {
+			continue
+		}
+		fmt.Fprintf(w, "  %-10s %s\n", c.name, c.summary)
+	}
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, `run "scrapeapi <command> -h" for the flags of a command`)
+}
+
+// newFlagSet returns a flag set for c that reports errors instead of exiting
+func newFlagSet(c *command) *flag.FlagSet {
+	fs := flag.NewFlagSet("scrapeapi "+c.name, flag.ContinueOnError)
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "usage: scrapeapi %s %s\n\n%s\n\nflags:\n", c.name, c.usage, c.summary)
+		fs.PrintDefaults()
+	}
+	return fs
+}
+
+// clientFlags are the connection flags every command talking to the server accepts
+type clientFlags struct {
+	baseURL     string
+	apiKey      string
+	profileName string
+	configPath  string
+
+	loaded *profile
+}
+
+func addClientFlags(fs *flag.FlagSet) *clientFlags {
+	cf := &clientFlags{}
+	fs.StringVar(&cf.baseURL, "base-url", "", "API base URL (default $SCRAPEAPI_BASE_URL, the profile's or "+defaultBaseURL+")")
+	fs.StringVar(&cf.apiKey, "api-key", "", "API key sent as a bearer token (default $SCRAPEAPI_API_KEY or the profile's)")
+	fs.StringVar(&cf.profileName, "profile", "", "config file profile to use (default $SCRAPEAPI_PROFILE or the config's default_profile)")
+	fs.StringVar(&cf.configPath, "config", "", "config file (default $SCRAPEAPI_CONFIG or "+defaultConfigPath()+")")
+	return cf
+}
+
+// profile loads the selected profile from the config file
+func (cf *clientFlags) profile() (*profile, error) {
+	if cf.loaded != nil {
+		return cf.loaded, nil
+	}
+	path := firstNonEmpty(cf.configPath, os.Getenv("SCRAPEAPI_CONFIG"))
+	cfg, err := loadConfig(firstNonEmpty(path, defaultConfigPath()), path != "")
+	if err != nil {
+		return nil, err
+	}
+	p, err := cfg.profile(firstNonEmpty(cf.profileName, os.Getenv("SCRAPEAPI_PROFILE")))
+	if err != nil {
+		return nil, err
+	}
+	cf.loaded = p
+	return p, nil
+}
+
+// client builds a client from the flags, falling back to the environment and then the profile
+func (cf *clientFlags) client() (*scrapeapi.Client, error) {
+	p, err := cf.profile()
+	if err != nil {
+		return nil, err
+	}
+	baseURL := firstNonEmpty(cf.baseURL, os.Getenv("SCRAPEAPI_BASE_URL"), p.BaseURL, defaultBaseURL)
+	apiKey := firstNonEmpty(cf.apiKey, os.Getenv("SCRAPEAPI_API_KEY"), p.apiKey())
+
+	opts := []scrapeapi.ClientOption{
+		scrapeapi.WithTracingDisabled(),
+		// Remember the jobs waited for, for request ID completion
+		scrapeapi.WithHooks(scrapeapi.Hooks{
+			OnJobStatusChange: func(ctx context.Context, from scrapeapi.Status, resp *scrapeapi.ScrapeResponse) {
+				if from == "" {
+					recent.add(resp.RequestID)
+				}
+			},
+		}),
+	}
+	if apiKey != "" {
+		opts = append(opts, scrapeapi.WithDefaultHeader("Authorization", "Bearer "+apiKey))
+	}
+	return scrapeapi.NewClient(strings.TrimRight(baseURL, "/"), opts...), nil
+}
+
+func firstNonEmpty(values ...string) string {
+	for _, v := range values {
+		if v != "" {
+			return v
+		}
+	}
+	return ""
+}
@@ -0,0 +1,291 @@
+package main
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+
+	scrapeapi "github.com/dir01/scrapeapi/sdk/go"
+	"gopkg.in/yaml.v3"
+)
+
+// outputFormats are the values --output accepts
+var outputFormats = []string{"json", "ndjson", "csv", "table", "yaml"}
+
+func addOutputFlag(fs *flag.FlagSet) *string {
+	return fs.String("output", "json", "result format: "+strings.Join(outputFormats, ", "))
+}
+
+func checkOutputFormat(format string) error {
+	for _, f := range outputFormats {
+		if f == format {
+			return nil
+		}
+	}
+	return fmt.Errorf("unknown output format %q, want one of %s", format, strings.Join(outputFormats, ", "))
+}
+
+// outputExt is the file extension for results written in format
+func outputExt(format string) string {
+	if format == "table" {
+		return "txt"
+	}
+	return format
+}
+
+// printResult writes the result of a completed job to stdout, warning on
+// stderr if the server found that it does not match the output schema
+func printResult(resp *scrapeapi.ScrapeResponse, format string, schema interface{}) error {
+	if msg := schemaMismatch(resp); msg != "" {
+		fmt.Fprintf(os.Stderr, "warning: result does not match the output schema: %s\n", msg)
+	}
+	return writeResult(os.Stdout, resp, format, schema)
+}
+
+// schemaMismatch returns why the server's schema_validation of the result
+// failed, or "" if it passed or there is none
+func schemaMismatch(resp *scrapeapi.ScrapeResponse) string {
+	envelope, _ := resp.Result.(map[string]interface{})
+	validation, _ := envelope["schema_validation"].(map[string]interface{})
+	if ok, isBool := validation["ok"].(bool); !isBool || ok {
+		return ""
+	}
+	if msg, _ := validation["error"].(string); msg != "" {
+		return msg
+	}
+	return "no details given"
+}
+
+// writeResult writes the result of resp to w in format, without the
+// schema_validation envelope the server wraps it in. Content returned by
+// the server, e.g. for markdown jobs, is written as is. The columns of csv
+// and table output come from schema when it is a JSON Schema, and from the
+// items themselves otherwise.
+func writeResult(w io.Writer, resp *scrapeapi.ScrapeResponse, format string, schema interface{}) error {
+	if resp.Content != "" {
+		_, err := fmt.Fprintln(w, resp.Content)
+		return err
+	}
+	result := resp.ResultData()
+
+	switch format {
+	case "yaml":
+		enc := yaml.NewEncoder(w)
+		enc.SetIndent(2)
+		if err := enc.Encode(result); err != nil {
+			return err
+		}
+		return enc.Close()
+	case "ndjson":
+		enc := json.NewEncoder(w)
+		enc.SetEscapeHTML(false)
+		items, _ := listItems(result, schema)
+		for _, item := range items {
+			if err := enc.Encode(item); err != nil {
+				return err
+			}
+		}
+		return nil
+	case "csv", "table":
+		items, itemSchema := listItems(result, schema)
+		columns := schemaColumns(itemSchema, "")
+		if len(columns) == 0 {
+			columns = itemColumns(items)
+		}
+		rows := make([][]string, len(items))
+		for i, item := range items {
+			flat := flattenItem(item)
+			rows[i] = make([]string, len(columns))
+			for j, col := range columns {
+				rows[i][j] = flat[col]
+			}
+		}
+		if format == "csv" {
+			cw := csv.NewWriter(w)
+			_ = cw.Write(columns)
+			_ = cw.WriteAll(rows)
+			return cw.Error()
+		}
+		return writeTable(w, columns, rows)
+	default:
+		return writeJSON(w, result)
+	}
+}
+
+// listItems finds the list in a result: the result itself, or the only
+// list-valued property of an object such as {"jobs": [...]}. Anything else
+// is a list of one. It also returns the schema of the items, if known.
+func listItems(result, schema interface{}) ([]interface{}, map[string]json.RawMessage) {
+	root := schemaObject(schema)
+	switch v := result.(type) {
+	case []interface{}:
+		return v, schemaObject(root["items"])
+	case map[string]interface{}:
+		var listKey string
+		for k, x := range v {
+			if _, ok := x.([]interface{}); ok {
+				if listKey != "" {
+					// Several lists; the object itself is the item
+					return []interface{}{v}, root
+				}
+				listKey = k
+			}
+		}
+		if listKey == "" {
+			return []interface{}{v}, root
+		}
+		props := schemaObject(root["properties"])
+		return v[listKey].([]interface{}), schemaObject(schemaObject(props[listKey])["items"])
+	case nil:
+		return nil, nil
+	}
+	return []interface{}{result}, nil
+}
+
+// schemaObject decodes a JSON Schema node, returning nil for anything that
+// is not an object
+func schemaObject(schema interface{}) map[string]json.RawMessage {
+	var raw []byte
+	switch s := schema.(type) {
+	case nil:
+		return nil
+	case json.RawMessage:
+		raw = s
+	default:
+		var err error
+		if raw, err = json.Marshal(s); err != nil {
+			return nil
+		}
+	}
+	var obj map[string]json.RawMessage
+	if err := json.Unmarshal(raw, &obj); err != nil {
+		return nil
+	}
+	return obj
+}
+
+// schemaColumns lists the columns of a flattened item in schema order:
+// nested objects become dotted columns such as "company.name"
+func schemaColumns(schema map[string]json.RawMessage, prefix string) []string {
+	props := schema["properties"]
+	if props == nil {
+		return nil
+	}
+	var columns []string
+	for _, name := range objectKeys(props) {
+		col := name
+		if prefix != "" {
+			col = prefix + "." + name
+		}
+		child := schemaObject(schemaObject(props)[name])
+		if nested := schemaColumns(child, col); len(nested) > 0 {
+			columns = append(columns, nested...)
+			continue
+		}
+		columns = append(columns, col)
+	}
+	return columns
+}
+
+// objectKeys returns the keys of a JSON object in document order
+func objectKeys(raw json.RawMessage) []string {
+	dec := json.NewDecoder(bytes.NewReader(raw))
+	if tok, err := dec.Token(); err != nil || tok != json.Delim('{') {
+		return nil
+	}
+	var keys []string
+	for dec.More() {
+		tok, err := dec.Token()
+		if err != nil {
+			return keys
+		}
+		keys = append(keys, tok.(string))
+		var skip json.RawMessage
+		if err := dec.Decode(&skip); err != nil {
+			return keys
+		}
+	}
+	return keys
+}
+
+// itemColumns derives columns from the items themselves when there is no schema
+func itemColumns(items []interface{}) []string {
+	seen := map[string]bool{}
+	for _, item := range items {
+		for k := range flattenItem(item) {
+			seen[k] = true
+		}
+	}
+	columns := make([]string, 0, len(seen))
+	for k := range seen {
+		columns = append(columns, k)
+	}
+	sort.Strings(columns)
+	return columns
+}
+
+// flatten writes the leaves of v into out keyed by dotted path; lists are
+// kept whole as JSON since they have no fixed number of columns
+func flatten(out map[string]string, prefix string, v interface{}) {
+	switch val := v.(type) {
+	case map[string]interface{}:
+		for k, x := range val {
+			key := k
+			if prefix != "" {
+				key = prefix + "." + k
+			}
+			flatten(out, key, x)
+		}
+	case nil:
+		out[prefix] = ""
+	case string:
+		out[prefix] = val
+	case float64:
+		out[prefix] = strconv.FormatFloat(val, 'f', -1, 64)
+	case []interface{}:
+		raw, _ := json.Marshal(val)
+		out[prefix] = string(raw)
+	default:
+		out[prefix] = fmt.Sprint(val)
+	}
+}
+
+// flattenItem flattens one list item; an item that is not an object is a
+// single "value" column
+func flattenItem(item interface{}) map[string]string {
+	out := map[string]string{}
+	if _, ok := item.(map[string]interface{}); ok {
+		flatten(out, "", item)
+	} else {
+		flatten(out, "value", item)
+	}
+	return out
+}
+
+// maxCellWidth truncates table cells so wide values do not wrap every row
+const maxCellWidth = 40
+
+func writeTable(w io.Writer, columns []string, rows [][]string) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, strings.ToUpper(strings.Join(columns, "\t")))
+	for _, row := range rows {
+		cells := make([]string, len(row))
+		for i, cell := range row {
+			cell = strings.Join(strings.Fields(cell), " ")
+			if r := []rune(cell); len(r) > maxCellWidth {
+				cell = string(r[:maxCellWidth-1]) + "…"
+			}
+			cells[i] = cell
+		}
+		fmt.Fprintln(tw, strings.Join(cells, "\t"))
+	}
+	return tw.Flush()
+}
@@ -0,0 +1,216 @@
+package main
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+
+	scrapeapi "github.com/dir01/scrapeapi/sdk/go"
+)
+
+const jobsSchema = `{
+	"type": "object",
+	"properties": {
+		"jobs": {
+			"type": "array",
+			"items": {
+				"type": "object",
+				"properties": {
+					"title":   {"type": "string"},
+					"company": {"type": "object", "properties": {"name": {"type": "string"}, "city": {"type": "string"}}},
+					"salary":  {"type": "number"}
+				}
+			}
+		}
+	}
+}`
+
+func decodeJSON(t *testing.T, s string) interface{} {
+	t.Helper()
+	var v interface{}
+	if err := json.Unmarshal([]byte(s), &v); err != nil {
+		t.Fatalf("decode %s: %v", s, err)
+	}
+	return v
+}
+
+func TestWriteResult(t *testing.T) {
+	const jobs = `{"jobs": [
+		{"title": "Go developer", "company": {"name": "Acme", "city": "Berlin"}, "salary": 5000},
+		{"title": "SRE", "company": {"name": "Initech"}, "tags": ["oncall"]}
+	]}`
+	envelope := `{"data": ` + jobs + `, "schema_validation": {"ok": true}}`
+
+	tests := []struct {
+		name   string
+		result string
+		format string
+		schema string
+		want   string
+	}{
+		{
+			name:   "csv from envelope with schema",
+			result: envelope,
+			format: "csv",
+			schema: jobsSchema,
+			want:   "title,company.name,company.city,salary\nGo developer,Acme,Berlin,5000\nSRE,Initech,,\n",
+		},
+		{
+			name:   "csv without envelope",
+			result: jobs,
+			format: "csv",
+			schema: jobsSchema,
+			want:   "title,company.name,company.city,salary\nGo developer,Acme,Berlin,5000\nSRE,Initech,,\n",
+		},
+		{
+			name:   "csv columns from items without schema",
+			result: envelope,
+			format: "csv",
+			want:   "company.city,company.name,salary,tags,title\nBerlin,Acme,5000,,Go developer\n,Initech,,\"[\"\"oncall\"\"]\",SRE\n",
+		},
+		{
+			name:   "ndjson from envelope",
+			result: envelope,
+			format: "ndjson",
+			want: `{"company":{"city":"Berlin","name":"Acme"},"salary":5000,"title":"Go developer"}` + "\n" +
+				`{"company":{"name":"Initech"},"tags":["oncall"],"title":"SRE"}` + "\n",
+		},
+		{
+			name:   "table from envelope",
+			result: envelope,
+			format: "table",
+			schema: jobsSchema,
+			want:   "TITLE         COMPANY.NAME  COMPANY.CITY  SALARY\nGo developer  Acme          Berlin        5000\nSRE           Initech                     \n",
+		},
+		{
+			name:   "json without envelope",
+			result: `{"data": {"title": "SRE"}, "schema_validation": {"ok": true}}`,
+			format: "json",
+			want:   "{\n  \"title\": \"SRE\"\n}\n",
+		},
+		{
+			name:   "yaml without envelope",
+			result: `{"data": {"title": "SRE"}, "schema_validation": {"ok": true}}`,
+			format: "yaml",
+			want:   "title: SRE\n",
+		},
+		{
+			name:   "json of a result the server did not wrap",
+			result: `{"data": {"title": "SRE"}}`,
+			format: "json",
+			want:   "{\n  \"data\": {\n    \"title\": \"SRE\"\n  }\n}\n",
+		},
+		{
+			name:   "csv of scalar list",
+			result: `{"data": ["a", "b"], "schema_validation": null}`,
+			format: "csv",
+			want:   "value\na\nb\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var schema interface{}
+			if tt.schema != "" {
+				schema = json.RawMessage(tt.schema)
+			}
+			resp := &scrapeapi.ScrapeResponse{Result: decodeJSON(t, tt.result)}
+			var b strings.Builder
+			if err := writeResult(&b, resp, tt.format, schema); err != nil {
+				t.Fatalf("writeResult: %v", err)
+			}
+			if b.String() != tt.want {
+				t.Errorf("writeResult =\n%s\nwant\n%s", b.String(), tt.want)
+			}
+		})
+	}
+}
+
+func TestSchemaMismatch(t *testing.T) {
+	tests := []struct {
+		name   string
+		result string
+		want   string
+	}{
+		{"passed", `{"data": {}, "schema_validation": {"ok": true}}`, ""},
+		{"failed", `{"data": {}, "schema_validation": {"ok": false, "error": "'title' is a required property"}}`, "'title' is a required property"},
+		{"failed without details", `{"data": {}, "schema_validation": {"ok": false}}`, "no details given"},
+		{"not validated", `{"data": {}, "schema_validation": null}`, ""},
+		{"no envelope", `[1, 2]`, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp := &scrapeapi.ScrapeResponse{Result: decodeJSON(t, tt.result)}
+			if got := schemaMismatch(resp); got != tt.want {
+				t.Errorf("schemaMismatch = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWriteResultContent(t *testing.T) {
+	resp := &scrapeapi.ScrapeResponse{Content: "# Title"}
+	var b strings.Builder
+	if err := writeResult(&b, resp, "csv", nil); err != nil {
+		t.Fatalf("writeResult: %v", err)
+	}
+	if b.String() != "# Title\n" {
+		t.Errorf("writeResult = %q, want the content as is", b.String())
+	}
+}
+
+func TestListItems(t *testing.T) {
+	tests := []struct {
+		name       string
+		result     string
+		schema     string
+		wantItems  int
+		wantSchema bool
+	}{
+		{"array", `[{"a": 1}, {"a": 2}]`, `{"type": "array", "items": {"type": "object"}}`, 2, true},
+		{"only list property", `{"jobs": [{}, {}, {}], "count": 3}`, jobsSchema, 3, true},
+		{"several lists", `{"a": [1], "b": [2]}`, jobsSchema, 1, true},
+		{"object without lists", `{"title": "x"}`, `{"type": "object"}`, 1, true},
+		{"scalar", `"x"`, ``, 1, false},
+		{"null", `null`, ``, 0, false},
+		{"list property without schema", `{"jobs": [{}]}`, ``, 1, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var schema interface{}
+			if tt.schema != "" {
+				schema = json.RawMessage(tt.schema)
+			}
+			items, itemSchema := listItems(decodeJSON(t, tt.result), schema)
+			if len(items) != tt.wantItems {
+				t.Errorf("got %d items, want %d", len(items), tt.wantItems)
+			}
+			if (itemSchema != nil) != tt.wantSchema {
+				t.Errorf("item schema = %v, want schema %v", itemSchema, tt.wantSchema)
+			}
+		})
+	}
+}
+
+func TestSchemaColumns(t *testing.T) {
+	tests := []struct {
+		name   string
+		schema string
+		want   []string
+	}{
+		{"document order", `{"properties": {"z": {}, "a": {}, "m": {}}}`, []string{"z", "a", "m"}},
+		{"nested objects", `{"properties": {"title": {}, "company": {"properties": {"name": {}, "address": {"properties": {"city": {}}}}}}}`,
+			[]string{"title", "company.name", "company.address.city"}},
+		{"object without properties", `{"properties": {"meta": {"type": "object"}}}`, []string{"meta"}},
+		{"no properties", `{"type": "object"}`, nil},
+		{"not a schema", `"a list of jobs"`, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := schemaColumns(schemaObject(json.RawMessage(tt.schema)), "")
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("schemaColumns = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,116 @@
+package main
+
+import (
+	"bufio"
+	"errors"
+	"os"
+	"path/filepath"
+	"slices"
+	"sync"
+)
+
+// maxRecentIDs is how many request IDs the completion cache keeps
+const maxRecentIDs = 100
+
+// recentIDs collects the request IDs a command saw, most recent last, so
+// they can be offered by shell completion. The cache is best-effort:
+// failures to read or write it are ignored.
+type recentIDs struct {
+	mu      sync.Mutex
+	added   []string
+	removed []string
+}
+
+var recent = &recentIDs{}
+
+func (r *recentIDs) add(ids ...string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, id := range ids {
+		if id != "" {
+			r.added = append(r.added, id)
+		}
+	}
+}
+
+func (r *recentIDs) remove(id string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.removed = append(r.removed, id)
+}
+
+// recentIDsPath is the cache file, one request ID per line, most recent first
+func recentIDsPath() string {
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(dir, "scrapeapi", "recent_ids")
+}
+
+// loadRecentIDs returns the cached request IDs, most recent first
+func loadRecentIDs() []string {
+	path := recentIDsPath()
+	if path == "" {
+		return nil
+	}
+	f, err := os.Open(path)
+	if err != nil {
+		return nil
+	}
+	defer f.Close()
+	var ids []string
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		if id := scanner.Text(); id != "" {
+			ids = append(ids, id)
+		}
+	}
+	return ids
+}
+
+// save merges the IDs seen by this command into the cache file
+func (r *recentIDs) save() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	path := recentIDsPath()
+	if path == "" || (len(r.added) == 0 && len(r.removed) == 0) {
+		return
+	}
+
+	var ids []string
+	for i := len(r.added) - 1; i >= 0; i-- {
+		ids = append(ids, r.added[i])
+	}
+	ids = append(ids, loadRecentIDs()...)
+
+	var kept []string
+	for _, id := range ids {
+		if !slices.Contains(kept, id) && !slices.Contains(r.removed, id) {
+			kept = append(kept, id)
+		}
+	}
+	if len(kept) > maxRecentIDs {
+		kept = kept[:maxRecentIDs]
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return
+	}
+	var data []byte
+	for _, id := range kept {
+		data = append(data, id...)
+		data = append(data, '\n')
+	}
+	// Write and rename, so a concurrent completion never reads half a file
+	tmp, err := os.CreateTemp(filepath.Dir(path), "recent_ids-*")
+	if err != nil {
+		return
+	}
+	_, err = tmp.Write(data)
+	if err := errors.Join(err, tmp.Close()); err != nil {
+		os.Remove(tmp.Name())
+		return
+	}
+	os.Rename(tmp.Name(), path)
+}
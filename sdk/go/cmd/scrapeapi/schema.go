@@ -0,0 +1,159 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"flag"
+	"fmt"
+	"go/token"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	scrapeapi "github.com/dir01/scrapeapi/sdk/go"
+)
+
+func init() {
+	register(&command{
+		name:        "schema",
+		usage:       "gen (--from-json FILE | --from-go PKG --type NAME) [--out FILE]",
+		summary:     "Generate an output schema from sample JSON or a Go struct",
+		subcommands: []string{"gen"},
+		run:         runSchema,
+	})
+}
+
+// sdkImportPath is the package the generated --from-go program imports
+const sdkImportPath = "github.com/dir01/scrapeapi/sdk/go"
+
+func runSchema(ctx context.Context, fs *flag.FlagSet, args []string) error {
+	if len(args) == 0 || args[0] != "gen" {
+		return errUsage
+	}
+	fromJSON := fs.String("from-json", "", "file holding a sample of the desired output")
+	fromGo := fs.String("from-go", "", "Go package holding the type, e.g. ./pkg/jobs")
+	typeName := fs.String("type", "", "exported Go struct type to generate the schema for, with --from-go")
+	out := fs.String("out", "", "file to write the schema to (default: stdout)")
+	if err := fs.Parse(args[1:]); err != nil {
+		return err
+	}
+	if fs.NArg() > 0 || (*fromJSON == "") == (*fromGo == "") {
+		return errUsage
+	}
+
+	var schema []byte
+	var err error
+	if *fromJSON != "" {
+		schema, err = schemaFromJSONFile(*fromJSON)
+	} else {
+		schema, err = schemaFromGo(ctx, *fromGo, *typeName)
+	}
+	if err != nil {
+		return err
+	}
+
+	if *out == "" {
+		_, err = os.Stdout.Write(schema)
+		return err
+	}
+	return os.WriteFile(*out, schema, 0o644)
+}
+
+func schemaFromJSONFile(path string) ([]byte, error) {
+	sample, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	schema, err := scrapeapi.SchemaFromJSON(sample)
+	if err != nil {
+		return nil, err
+	}
+	var buf bytes.Buffer
+	if err := writeJSON(&buf, schema); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// schemaFromGo runs SchemaFor on the type by building a throwaway program
+// inside the module of pkg, so the schema is exactly what Go code using the
+// SDK would send. The module must depend on the SDK.
+func schemaFromGo(ctx context.Context, pkg, typeName string) ([]byte, error) {
+	if !token.IsIdentifier(typeName) || !token.IsExported(typeName) {
+		return nil, fmt.Errorf("--type must name an exported type, got %q", typeName)
+	}
+
+	listed, err := goCommand(ctx, "", "list", "-f", "{{.ImportPath}}\n{{if .Module}}{{.Module.Dir}}{{end}}", pkg)
+	if err != nil {
+		return nil, err
+	}
+	importPath, moduleDir, _ := strings.Cut(strings.TrimSpace(string(listed)), "\n")
+	if moduleDir == "" {
+		return nil, fmt.Errorf("%s is not in a Go module", pkg)
+	}
+
+	// Directories starting with "." are ignored by ./... patterns, so the
+	// program never shows up in the module's own builds
+	dir, err := os.MkdirTemp(moduleDir, ".scrapeapi-schema-")
+	if err != nil {
+		return nil, err
+	}
+	defer os.RemoveAll(dir)
+
+	program := fmt.Sprintf(schemaProgram, sdkImportPath, importPath, typeName, typeName)
+	if err := os.WriteFile(filepath.Join(dir, "main.go"), []byte(program), 0o644); err != nil {
+		return nil, err
+	}
+	schema, err := goCommand(ctx, moduleDir, "run", "./"+filepath.Base(dir))
+	if err != nil && strings.Contains(err.Error(), sdkImportPath) {
+		return nil, fmt.Errorf("%w\nthe module of %s must require %s: run \"go get %s\" in %s", err, pkg, sdkImportPath, sdkImportPath, moduleDir)
+	}
+	return schema, err
+}
+
+// schemaProgram prints the schema of a type to stdout and problems with its
+// jsonschema tags to stderr
+const schemaProgram = `package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	scrapeapi %q
+	target %q
+)
+
+func main() {
+	if err := scrapeapi.CheckSchemaTags[target.%s](); err != nil {
+		fmt.Fprintln(os.Stderr, "warning:", err)
+	}
+	enc := json.NewEncoder(os.Stdout)
+	enc.SetIndent("", "  ")
+	enc.SetEscapeHTML(false)
+	if err := enc.Encode(scrapeapi.SchemaFor[target.%s]()); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
+`
+
+// goCommand runs the go tool in dir and returns its stdout; stderr is
+// passed through except on failure, when it becomes the error
+func goCommand(ctx context.Context, dir string, args ...string) ([]byte, error) {
+	cmd := exec.CommandContext(ctx, "go", args...)
+	cmd.Dir = dir
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if err != nil {
+		msg := strings.TrimSpace(stderr.String())
+		if msg == "" {
+			msg = err.Error()
+		}
+		return nil, fmt.Errorf("go %s: %s", args[0], msg)
+	}
+	os.Stderr.Write(stderr.Bytes())
+	return out, nil
+}
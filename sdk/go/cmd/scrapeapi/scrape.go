@@ -0,0 +1,160 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"time"
+
+	scrapeapi "github.com/dir01/scrapeapi/sdk/go"
+)
+
+func init() {
+	register(&command{
+		name:    "scrape",
+		usage:   "--url URL --prompt PROMPT [--schema FILE] [--graph GRAPH]",
+		summary: "Run a scraping job, wait for it and print its result",
+		run:     runScrape,
+	})
+}
+
+// jobFlags describe the job to submit; shared by the commands that start jobs
+type jobFlags struct {
+	url           string
+	prompt        string
+	schema        string
+	graph         string
+	model         string
+	timeout       time.Duration
+	pollEvery     time.Duration
+	serverTimeout int
+	output        *string
+}
+
+func addJobFlags(fs *flag.FlagSet) *jobFlags {
+	jf := addRequestFlags(fs)
+	addWaitFlags(fs, jf)
+	return jf
+}
+
+// addWaitFlags adds the flags for waiting for the job and printing its result
+func addWaitFlags(fs *flag.FlagSet, jf *jobFlags) {
+	fs.DurationVar(&jf.timeout, "timeout", 10*time.Minute, "how long to wait for the job")
+	fs.DurationVar(&jf.pollEvery, "poll-interval", 2*time.Second, "how often to poll the job")
+	jf.output = addOutputFlag(fs)
+}
+
+// addRequestFlags adds only the flags that describe the request, for
+// commands that check a job without running it
+func addRequestFlags(fs *flag.FlagSet) *jobFlags {
+	jf := &jobFlags{}
+	fs.StringVar(&jf.url, "url", "", "URL of the page to scrape")
+	fs.StringVar(&jf.prompt, "prompt", "", "what to extract from the page")
+	fs.StringVar(&jf.schema, "schema", "", "file holding a JSON Schema or an example of the output")
+	fs.StringVar(&jf.graph, "graph", string(scrapeapi.GraphSmart), "graph to run: smart, multi, search, sitemap, crawl or feed")
+	fs.StringVar(&jf.model, "model", "", "LLM model, e.g. openai/gpt-4o-mini (default: the server's)")
+	fs.IntVar(&jf.serverTimeout, "server-timeout", 0, "server-side timeout of the job in seconds (default: the server's)")
+	return jf
+}
+
+// request builds the request for url, which overrides --url when not empty
+func (jf *jobFlags) request(url string) (*scrapeapi.ScrapeRequest, error) {
+	req := &scrapeapi.ScrapeRequest{
+		Graph:      scrapeapi.Graph(jf.graph),
+		UserPrompt: jf.prompt,
+		TimeoutSec: jf.serverTimeout,
+	}
+	if url == "" {
+		url = jf.url
+	}
+	if url != "" {
+		req.WebsiteURL = scrapeapi.String(url)
+	}
+	if jf.model != "" {
+		req.LLM = &scrapeapi.LLMConfig{Model: jf.model}
+	}
+	if jf.schema != "" {
+		schema, err := readSchema(jf.schema)
+		if err != nil {
+			return nil, err
+		}
+		req.OutputSchema = schema
+	}
+	return req, nil
+}
+
+// applyProfile uses the profile's defaults for the job flags not given on the command line
+func (jf *jobFlags) applyProfile(fs *flag.FlagSet, p *profile) {
+	set := map[string]bool{}
+	fs.Visit(func(f *flag.Flag) { set[f.Name] = true })
+	if !set["model"] && p.Model != "" {
+		jf.model = p.Model
+	}
+	if !set["timeout"] && p.Timeout > 0 {
+		jf.timeout = p.Timeout
+	}
+}
+
+func (jf *jobFlags) waitOptions() []scrapeapi.WaitOption {
+	return []scrapeapi.WaitOption{
+		scrapeapi.WithPollInterval(jf.pollEvery),
+		scrapeapi.WithMaxWait(jf.timeout),
+		scrapeapi.WithCancelOnContextDone(),
+	}
+}
+
+// readSchema reads an output schema file: a JSON object is sent as a JSON
+// Schema, kept raw so its property order survives, and anything else as an
+// example of the output
+func readSchema(path string) (interface{}, error) {
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read schema: %w", err)
+	}
+	var schema map[string]json.RawMessage
+	if err := json.Unmarshal(raw, &schema); err == nil {
+		var compact bytes.Buffer
+		if err := json.Compact(&compact, raw); err != nil {
+			return nil, fmt.Errorf("read schema: %w", err)
+		}
+		return json.RawMessage(compact.Bytes()), nil
+	}
+	return string(raw), nil
+}
+
+func runScrape(ctx context.Context, fs *flag.FlagSet, args []string) error {
+	cf := addClientFlags(fs)
+	jf := addJobFlags(fs)
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() > 0 {
+		return errUsage
+	}
+	if err := checkOutputFormat(*jf.output); err != nil {
+		return err
+	}
+
+	p, err := cf.profile()
+	if err != nil {
+		return err
+	}
+	jf.applyProfile(fs, p)
+	client, err := cf.client()
+	if err != nil {
+		return err
+	}
+
+	req, err := jf.request("")
+	if err != nil {
+		return err
+	}
+	resp, err := client.ScrapeAndWait(ctx, req, jf.waitOptions()...)
+	if err != nil {
+		return err
+	}
+	return printResult(resp, *jf.output, req.OutputSchema)
+}
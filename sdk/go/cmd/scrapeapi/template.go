@@ -0,0 +1,216 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"slices"
+	"sort"
+	"strings"
+	"text/template"
+
+	scrapeapi "github.com/dir01/scrapeapi/sdk/go"
+	"gopkg.in/yaml.v3"
+)
+
+func init() {
+	register(&command{
+		name:    "run",
+		usage:   "--template FILE [--set NAME=VALUE]... [--print]",
+		summary: "Run a job defined by a YAML request template",
+		run:     runTemplate,
+	})
+}
+
+// varsFlag collects repeated --set NAME=VALUE flags
+type varsFlag map[string]string
+
+func (v varsFlag) String() string {
+	return ""
+}
+
+func (v varsFlag) Set(s string) error {
+	name, value, ok := strings.Cut(s, "=")
+	if !ok || name == "" {
+		return fmt.Errorf("want NAME=VALUE, got %q", s)
+	}
+	v[name] = value
+	return nil
+}
+
+func runTemplate(ctx context.Context, fs *flag.FlagSet, args []string) error {
+	cf := addClientFlags(fs)
+	jf := &jobFlags{}
+	addWaitFlags(fs, jf)
+	path := fs.String("template", "", "YAML file holding the request template")
+	printOnly := fs.Bool("print", false, "print the rendered request as JSON instead of running it")
+	vars := varsFlag{}
+	fs.Var(vars, "set", "set a template variable, as NAME=VALUE; may be repeated")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() > 0 || *path == "" {
+		return errUsage
+	}
+	if err := checkOutputFormat(*jf.output); err != nil {
+		return err
+	}
+
+	req, err := loadTemplate(*path, vars)
+	if err != nil {
+		return err
+	}
+	if *printOnly {
+		return writeJSON(os.Stdout, req)
+	}
+
+	p, err := cf.profile()
+	if err != nil {
+		return err
+	}
+	jf.applyProfile(fs, p)
+	if jf.model != "" && (req.LLM == nil || req.LLM.Model == "") {
+		if req.LLM == nil {
+			req.LLM = &scrapeapi.LLMConfig{}
+		}
+		req.LLM.Model = jf.model
+	}
+	client, err := cf.client()
+	if err != nil {
+		return err
+	}
+
+	resp, err := client.ScrapeAndWait(ctx, req, jf.waitOptions()...)
+	if err != nil {
+		return err
+	}
+	return printResult(resp, *jf.output, req.OutputSchema)
+}
+
+// loadTemplate reads a request template: a ScrapeRequest in YAML, with the
+// JSON field names, whose string values are text/template templates over
+// the variables. The top-level vars key, which is not part of the request,
+// holds defaults that set overrides:
+//
+//	vars:
+//	  limit: "20"
+//	graph: smart
+//	website_url: "{{.url}}"
+//	user_prompt: Extract the first {{.limit}} job listings
+//	max_results: !!int "{{.limit}}"
+//
+// Rendered values are strings unless tagged with another type, as
+// max_results is above. Since only values are rendered, a variable can
+// never change the structure of the request.
+func loadTemplate(path string, set map[string]string) (*scrapeapi.ScrapeRequest, error) {
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read template: %w", err)
+	}
+	var doc yaml.Node
+	if err := yaml.Unmarshal(raw, &doc); err != nil {
+		return nil, fmt.Errorf("template %s: %w", path, err)
+	}
+	if len(doc.Content) == 0 || doc.Content[0].Kind != yaml.MappingNode {
+		return nil, fmt.Errorf("template %s: must be a mapping of request fields", path)
+	}
+	root := doc.Content[0]
+
+	vars := map[string]string{}
+	for i := 0; i+1 < len(root.Content); i += 2 {
+		if root.Content[i].Value != "vars" {
+			continue
+		}
+		if err := root.Content[i+1].Decode(&vars); err != nil {
+			return nil, fmt.Errorf("template %s: vars: %w", path, err)
+		}
+		root.Content = append(root.Content[:i], root.Content[i+2:]...)
+		break
+	}
+	for name, value := range set {
+		vars[name] = value
+	}
+
+	if err := render(root, vars); err != nil {
+		return nil, fmt.Errorf("template %s: %w", path, err)
+	}
+
+	// Through JSON, so the request's own field names and decoding apply
+	var fields map[string]interface{}
+	if err := root.Decode(&fields); err != nil {
+		return nil, fmt.Errorf("template %s: %w", path, err)
+	}
+	data, err := json.Marshal(fields)
+	if err != nil {
+		return nil, fmt.Errorf("template %s: %w", path, err)
+	}
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.DisallowUnknownFields()
+	var req scrapeapi.ScrapeRequest
+	if err := dec.Decode(&req); err != nil {
+		return nil, fmt.Errorf("template %s: %w", path, err)
+	}
+	return &req, nil
+}
+
+// templateValues returns the scalar values under node that hold templates
+func templateValues(node *yaml.Node) []*yaml.Node {
+	var values []*yaml.Node
+	switch node.Kind {
+	case yaml.MappingNode:
+		// Keys are left alone; only values are rendered
+		for i := 1; i < len(node.Content); i += 2 {
+			values = append(values, templateValues(node.Content[i])...)
+		}
+	case yaml.SequenceNode:
+		for _, child := range node.Content {
+			values = append(values, templateValues(child)...)
+		}
+	case yaml.ScalarNode:
+		if strings.Contains(node.Value, "{{") {
+			values = append(values, node)
+		}
+	}
+	return values
+}
+
+// render renders the templates in the values under node, failing with
+// every missing variable if any are not in vars
+func render(node *yaml.Node, vars map[string]string) error {
+	values := templateValues(node)
+	tmpls := make([]*template.Template, len(values))
+	var missing []string
+	for i, value := range values {
+		tmpl, err := template.New("value").Option("missingkey=error").Parse(value.Value)
+		if err != nil {
+			return fmt.Errorf("line %d: %w", value.Line, err)
+		}
+		tmpls[i] = tmpl
+		// ParsePrompt lists the variables a template refers to
+		prompt, err := scrapeapi.ParsePrompt(value.Value)
+		if err != nil {
+			return fmt.Errorf("line %d: %w", value.Line, err)
+		}
+		for _, name := range prompt.Variables() {
+			if _, ok := vars[name]; !ok && !slices.Contains(missing, name) {
+				missing = append(missing, name)
+			}
+		}
+	}
+	if len(missing) > 0 {
+		sort.Strings(missing)
+		return fmt.Errorf("missing variables %s; set them with --set NAME=VALUE", strings.Join(missing, ", "))
+	}
+
+	for i, value := range values {
+		var b strings.Builder
+		if err := tmpls[i].Execute(&b, vars); err != nil {
+			return fmt.Errorf("line %d: %w", value.Line, err)
+		}
+		value.Value = b.String()
+	}
+	return nil
+}
@@ -0,0 +1,564 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	scrapeapi "github.com/dir01/scrapeapi/sdk/go"
+)
+
+func init() {
+	register(&command{
+		name:    "tui",
+		usage:   "[--status STATUS] [--refresh D] [--limit N]",
+		summary: "Show a live dashboard of jobs, with their results, logs and artifacts",
+		run:     runTUI,
+	})
+}
+
+func runTUI(ctx context.Context, fs *flag.FlagSet, args []string) error {
+	cf := addClientFlags(fs)
+	status := fs.String("status", "", "only jobs in this status")
+	refresh := fs.Duration("refresh", 2*time.Second, "how often to refresh the jobs")
+	limit := fs.Int("limit", 100, "show at most this many jobs")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() > 0 || *refresh <= 0 || *limit <= 0 {
+		return errUsage
+	}
+	opts := scrapeapi.ListOptions{Status: scrapeapi.Status(*status), Limit: *limit}
+	if opts.Status != "" && !opts.Status.IsValid() {
+		return fmt.Errorf("unknown status %q", *status)
+	}
+	if !isTerminal(os.Stdin) || !isTerminal(os.Stdout) {
+		return errors.New("needs a terminal")
+	}
+
+	client, err := cf.client()
+	if err != nil {
+		return err
+	}
+	restore, err := cbreakTerminal()
+	if err != nil {
+		return err
+	}
+	defer restore()
+
+	d := &dashboard{
+		client:   client,
+		opts:     opts,
+		w:        bufio.NewWriter(os.Stdout),
+		finished: map[string]time.Time{},
+		active:   map[string]bool{},
+	}
+	// Alternate screen and hidden cursor, undone on the way out
+	fmt.Fprint(os.Stdout, "\033[?1049h\033[?25l")
+	defer fmt.Fprint(os.Stdout, "\033[?25h\033[?1049l")
+
+	keys := make(chan string)
+	go readKeys(os.Stdin, keys)
+	ticker := time.NewTicker(*refresh)
+	defer ticker.Stop()
+
+	d.refresh(ctx)
+	for {
+		d.draw()
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+			d.refresh(ctx)
+		case key, ok := <-keys:
+			if !ok || !d.handleKey(ctx, key) {
+				return nil
+			}
+		}
+	}
+}
+
+// cbreakTerminal turns off line buffering and echo, so keys are read as
+// they are pressed while Ctrl-C still interrupts, and returns a function
+// restoring the previous settings. It uses stty to stay free of
+// platform-specific code.
+func cbreakTerminal() (func(), error) {
+	saved, err := stty("-g")
+	if err != nil {
+		return nil, fmt.Errorf("read terminal settings: %w", err)
+	}
+	if _, err := stty("-icanon", "-echo", "min", "1", "time", "0"); err != nil {
+		return nil, fmt.Errorf("configure terminal: %w", err)
+	}
+	return func() { stty(strings.TrimSpace(saved)) }, nil
+}
+
+func stty(args ...string) (string, error) {
+	cmd := exec.Command("stty", args...)
+	cmd.Stdin = os.Stdin
+	out, err := cmd.Output()
+	return string(out), err
+}
+
+// terminalSize returns the rows and columns of the terminal, with a
+// fallback when stty cannot tell
+func terminalSize() (rows, cols int) {
+	out, err := stty("size")
+	if err == nil {
+		if _, err := fmt.Sscan(out, &rows, &cols); err == nil && rows > 0 && cols > 0 {
+			return rows, cols
+		}
+	}
+	return 24, 80
+}
+
+// readKeys sends the keys read from r to keys, named for the ones that
+// are not plain characters, and closes keys when r fails
+func readKeys(r io.Reader, keys chan<- string) {
+	defer close(keys)
+	buf := make([]byte, 16)
+	for {
+		n, err := r.Read(buf)
+		if err != nil {
+			return
+		}
+		switch s := string(buf[:n]); s {
+		case "\033[A", "\033OA":
+			keys <- "up"
+		case "\033[B", "\033OB":
+			keys <- "down"
+		case "\033[5~":
+			keys <- "pgup"
+		case "\033[6~":
+			keys <- "pgdn"
+		case "\033":
+			keys <- "esc"
+		case "\r", "\n":
+			keys <- "enter"
+		case "\x7f", "\b":
+			keys <- "backspace"
+		default:
+			keys <- s
+		}
+	}
+}
+
+// dashboard is the state of the tui command
+type dashboard struct {
+	client *scrapeapi.Client
+	opts   scrapeapi.ListOptions
+	w      *bufio.Writer
+	rows   int
+	cols   int
+
+	jobs     []*scrapeapi.ScrapeResponse
+	selected int
+	updated  time.Time
+	err      error
+	// The API reports no finish time, so a job's duration is known only
+	// when the dashboard saw it finish
+	active   map[string]bool
+	finished map[string]time.Time
+
+	// detail is the job being looked at; nil on the job list
+	detail *jobDetail
+}
+
+// jobDetail is the drill-down view of one job
+type jobDetail struct {
+	job          *scrapeapi.ScrapeResponse
+	tab          string
+	scroll       int
+	logs         []scrapeapi.LogEntry
+	logsErr      error
+	artifacts    []scrapeapi.Artifact
+	artifactsErr error
+	selected     int
+	message      string
+	// follow keeps the end of the log in view until the user scrolls up
+	follow bool
+}
+
+// dashboardTabs are the views of a job, by the key that opens them
+var dashboardTabs = []struct{ key, name string }{
+	{"r", "result"},
+	{"l", "logs"},
+	{"a", "artifacts"},
+}
+
+// refresh reloads the job list and, if one is open, the job
+func (d *dashboard) refresh(ctx context.Context) {
+	d.rows, d.cols = terminalSize()
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	list, err := d.client.ListScrapes(ctx, d.opts)
+	d.err = err
+	if err == nil {
+		d.jobs = d.jobs[:0]
+		for i := range list.Items {
+			d.track(&list.Items[i])
+			d.jobs = append(d.jobs, &list.Items[i])
+		}
+		d.selected = min(d.selected, max(len(d.jobs)-1, 0))
+		d.updated = time.Now()
+	}
+
+	if d.detail != nil {
+		d.refreshDetail(ctx)
+	}
+}
+
+// track records when jobs finish, for their duration
+func (d *dashboard) track(job *scrapeapi.ScrapeResponse) {
+	switch {
+	case !job.Status.IsTerminal():
+		d.active[job.RequestID] = true
+	case d.active[job.RequestID]:
+		if _, ok := d.finished[job.RequestID]; !ok {
+			d.finished[job.RequestID] = time.Now()
+		}
+	}
+}
+
+func (d *dashboard) refreshDetail(ctx context.Context) {
+	detail := d.detail
+	wasTerminal := detail.job.Status.IsTerminal()
+	if job, err := d.client.GetScrape(ctx, detail.job.RequestID); err == nil {
+		d.track(job)
+		detail.job = job
+	}
+
+	var after int64
+	if len(detail.logs) > 0 {
+		after = detail.logs[len(detail.logs)-1].Sequence
+	}
+	logs, err := d.client.GetScrapeLogs(ctx, detail.job.RequestID, scrapeapi.LogOptions{After: after})
+	detail.logsErr = err
+	if err == nil {
+		detail.logs = append(detail.logs, logs.Entries...)
+	}
+
+	// Artifacts are only captured as the job runs, so reload them until it is done
+	if detail.artifacts == nil || !wasTerminal {
+		artifacts, err := d.client.GetScrapeArtifacts(ctx, detail.job.RequestID)
+		detail.artifactsErr = err
+		if err == nil {
+			detail.artifacts = artifacts
+		}
+	}
+}
+
+// handleKey updates the dashboard for key, returning false to quit
+func (d *dashboard) handleKey(ctx context.Context, key string) bool {
+	if key == "q" {
+		return false
+	}
+
+	if d.detail == nil {
+		switch key {
+		case "up", "k":
+			d.selected = max(d.selected-1, 0)
+		case "down", "j":
+			d.selected = min(d.selected+1, max(len(d.jobs)-1, 0))
+		case "enter":
+			if d.selected < len(d.jobs) {
+				d.detail = &jobDetail{job: d.jobs[d.selected], tab: "result"}
+				d.refreshDetail(ctx)
+			}
+		}
+		return true
+	}
+
+	detail := d.detail
+	for _, tab := range dashboardTabs {
+		if key == tab.key {
+			detail.tab, detail.scroll, detail.message = tab.name, 0, ""
+			detail.follow = tab.name == "logs"
+			return true
+		}
+	}
+	page := max(d.rows-12, 1)
+	switch key {
+	case "esc", "backspace":
+		d.detail = nil
+	case "up", "k":
+		if detail.tab == "artifacts" {
+			detail.selected = max(detail.selected-1, 0)
+		} else {
+			detail.scroll = max(detail.scroll-1, 0)
+			detail.follow = false
+		}
+	case "down", "j":
+		if detail.tab == "artifacts" {
+			detail.selected = min(detail.selected+1, max(len(detail.artifacts)-1, 0))
+		} else {
+			detail.scroll++
+		}
+	case "pgup":
+		detail.scroll = max(detail.scroll-page, 0)
+		detail.follow = false
+	case "pgdn":
+		detail.scroll += page
+	case "s":
+		if detail.tab == "artifacts" && detail.selected < len(detail.artifacts) {
+			detail.message = d.saveArtifact(ctx, detail.artifacts[detail.selected])
+		}
+	}
+	return true
+}
+
+// saveArtifact downloads an artifact into the current directory and
+// returns a message saying how that went
+func (d *dashboard) saveArtifact(ctx context.Context, a scrapeapi.Artifact) string {
+	name := filepath.Base(a.ID) + artifactExt(a)
+	f, err := os.Create(name)
+	if err != nil {
+		return err.Error()
+	}
+	err = d.client.DownloadArtifact(ctx, a.ID, f)
+	if err := errors.Join(err, f.Close()); err != nil {
+		os.Remove(name)
+		return "download failed: " + err.Error()
+	}
+	return "saved " + name
+}
+
+func artifactExt(a scrapeapi.Artifact) string {
+	switch a.Type {
+	case scrapeapi.ArtifactScreenshot:
+		return ".png"
+	case scrapeapi.ArtifactHTML:
+		return ".html"
+	case scrapeapi.ArtifactMarkdown:
+		return ".md"
+	case scrapeapi.ArtifactPDF:
+		return ".pdf"
+	}
+	return ""
+}
+
+// draw repaints the whole screen
+func (d *dashboard) draw() {
+	fmt.Fprint(d.w, "\033[H\033[2J")
+	if d.detail == nil {
+		d.drawList()
+	} else {
+		d.drawDetail()
+	}
+	d.w.Flush()
+}
+
+// line writes one line of the screen, cut to its width. The terminal
+// still translates newlines, so none need a carriage return.
+func (d *dashboard) line(format string, args ...interface{}) {
+	fmt.Fprint(d.w, fit(fmt.Sprintf(format, args...), d.cols), "\n")
+}
+
+func (d *dashboard) drawList() {
+	updated := "loading"
+	if !d.updated.IsZero() {
+		updated = "updated " + d.updated.Format(time.TimeOnly)
+	}
+	d.line("\033[1mscrapeapi\033[0m  %d jobs, %s   ↑/↓ select  enter open  q quit", len(d.jobs), updated)
+	if d.err != nil {
+		d.line("\033[31m%v\033[0m", d.err)
+	} else {
+		d.line("")
+	}
+
+	const row = "%-24s %-10s %-8s %9s %9s  %s"
+	d.line("\033[1m"+row+"\033[0m", "REQUEST_ID", "STATUS", "GRAPH", "DURATION", "COST", "SOURCE")
+	visible := max(d.rows-4, 1)
+	offset := max(d.selected-visible+1, 0)
+	for i := offset; i < len(d.jobs) && i < offset+visible; i++ {
+		job := d.jobs[i]
+		text := fit(fmt.Sprintf(row, fit(job.RequestID, 24), job.Status, job.Graph, d.duration(job), jobCost(job), jobSource(job)), d.cols)
+		if i == d.selected {
+			// Reverse video marks the selected job; status colors would fight with it
+			fmt.Fprint(d.w, "\033[7m", text, "\033[0m\n")
+			continue
+		}
+		fmt.Fprint(d.w, statusColor(job.Status), text, "\033[0m\n")
+	}
+}
+
+func (d *dashboard) drawDetail() {
+	detail := d.detail
+	job := detail.job
+	d.line("\033[1m%s%s\033[0m", statusColor(job.Status), describeJob(job))
+	d.line("graph %s  source %s", job.Graph, jobSource(job))
+	created := "-"
+	if job.CreatedAt != nil {
+		created = job.CreatedAt.Local().Format(time.DateTime)
+	}
+	d.line("created %s  duration %s  cost %s", created, strings.TrimSpace(d.duration(job)), jobCost(job))
+	if job.Error != "" {
+		d.line("\033[31merror: %s\033[0m", job.Error)
+	} else {
+		d.line("")
+	}
+
+	var tabs []string
+	for _, tab := range dashboardTabs {
+		label := "[" + tab.key + "]" + strings.TrimPrefix(tab.name, tab.key)
+		if tab.name == detail.tab {
+			label = "\033[7m" + label + "\033[0m"
+		}
+		tabs = append(tabs, label)
+	}
+	hint := "↑/↓ scroll  esc back  q quit"
+	if detail.tab == "artifacts" {
+		hint = "↑/↓ select  s save  esc back  q quit"
+	}
+	d.line("%s   %s", strings.Join(tabs, "  "), hint)
+	d.line("")
+
+	body := d.detailBody()
+	visible := max(d.rows-7, 1)
+	if detail.follow {
+		detail.scroll = max(len(body)-visible, 0)
+	}
+	detail.scroll = min(detail.scroll, max(len(body)-visible, 0))
+	for i := detail.scroll; i < len(body) && i < detail.scroll+visible; i++ {
+		d.line("%s", body[i])
+	}
+	if detail.message != "" {
+		d.line("")
+		d.line("%s", detail.message)
+	}
+}
+
+// detailBody returns the lines of the open tab
+func (d *dashboard) detailBody() []string {
+	detail := d.detail
+	job := detail.job
+	switch detail.tab {
+	case "result":
+		if job.Status != scrapeapi.StatusCompleted {
+			return []string{"no result: the job is " + string(job.Status)}
+		}
+		var buf bytes.Buffer
+		if err := writeJSON(&buf, job.Result); err != nil {
+			return []string{err.Error()}
+		}
+		return strings.Split(strings.TrimRight(buf.String(), "\n"), "\n")
+
+	case "logs":
+		if detail.logsErr != nil {
+			return []string{"logs: " + detail.logsErr.Error()}
+		}
+		if len(detail.logs) == 0 {
+			return []string{"no log entries yet"}
+		}
+		lines := make([]string, len(detail.logs))
+		for i, entry := range detail.logs {
+			lines[i] = strings.TrimSuffix(formatLogEntry(entry, true), "\n")
+		}
+		return lines
+
+	case "artifacts":
+		if detail.artifactsErr != nil {
+			return []string{"artifacts: " + detail.artifactsErr.Error()}
+		}
+		if len(detail.artifacts) == 0 {
+			return []string{"no artifacts"}
+		}
+		lines := []string{fmt.Sprintf("  %-28s %-11s %10s  %s", "ID", "TYPE", "SIZE", "CONTENT_TYPE")}
+		for i, a := range detail.artifacts {
+			marker := "  "
+			if i == detail.selected {
+				marker = "> "
+			}
+			lines = append(lines, fmt.Sprintf("%s%-28s %-11s %10s  %s", marker, fit(a.ID, 28), a.Type, formatSize(a.Size), a.ContentType))
+		}
+		return lines
+	}
+	return nil
+}
+
+// duration is how long a job has run, known for finished jobs only when
+// the dashboard saw them finish
+func (d *dashboard) duration(job *scrapeapi.ScrapeResponse) string {
+	if job.CreatedAt == nil {
+		return "-"
+	}
+	end := time.Now()
+	if job.Status.IsTerminal() {
+		var ok bool
+		if end, ok = d.finished[job.RequestID]; !ok {
+			return "-"
+		}
+	}
+	return end.Sub(*job.CreatedAt).Round(time.Second).String()
+}
+
+func jobCost(job *scrapeapi.ScrapeResponse) string {
+	if job.Usage == nil {
+		return "-"
+	}
+	return "$" + strconv.FormatFloat(job.Usage.CostUSD, 'f', 4, 64)
+}
+
+func statusColor(status scrapeapi.Status) string {
+	switch status {
+	case scrapeapi.StatusRunning:
+		return "\033[33m"
+	case scrapeapi.StatusCompleted:
+		return "\033[32m"
+	case scrapeapi.StatusFailed:
+		return "\033[31m"
+	case scrapeapi.StatusCanceled:
+		return "\033[2m"
+	}
+	return ""
+}
+
+func formatSize(n int64) string {
+	switch {
+	case n >= 1<<20:
+		return fmt.Sprintf("%.1f MB", float64(n)/(1<<20))
+	case n >= 1<<10:
+		return fmt.Sprintf("%.1f KB", float64(n)/(1<<10))
+	}
+	return fmt.Sprintf("%d B", n)
+}
+
+// fit cuts s to width visible characters, not counting ANSI escape
+// sequences, which are kept so colors are still reset
+func fit(s string, width int) string {
+	var b strings.Builder
+	visible := 0
+	for i := 0; i < len(s); {
+		if s[i] == '\033' {
+			end := strings.IndexFunc(s[i+1:], func(r rune) bool { return r >= '@' && r <= '~' && r != '[' })
+			if end < 0 {
+				break
+			}
+			b.WriteString(s[i : i+end+2])
+			i += end + 2
+			continue
+		}
+		r, size := utf8.DecodeRuneInString(s[i:])
+		if visible < width {
+			if r == '\t' {
+				r = ' '
+			}
+			b.WriteRune(r)
+			visible++
+		}
+		i += size
+	}
+	return b.String()
+}
@@ -0,0 +1,96 @@
+package scrapeapi
+
+import (
+	"bytes"
+	"encoding/json"
+)
+
+// FieldValue is an extracted value together with the server's confidence in
+// it, as returned for requests with IncludeConfidence. Use it in place of T
+// in result structs; SchemaFor still describes the plain T to the server.
+//
+//	type Job struct {
+//		Title  scrapeapi.FieldValue[string] `json:"title"`
+//		Salary scrapeapi.FieldValue[int]    `json:"salary"`
+//	}
+type FieldValue[T any] struct {
+	Value T
+	// Confidence is the server's confidence in Value from 0 to 1,
+	// or 1 for values returned without a score
+	Confidence float64
+}
+
+type fieldValueJSON[T any] struct {
+	Value      T       `json:"value"`
+	Confidence float64 `json:"confidence"`
+}
+
+// UnmarshalJSON accepts both the {"value": ..., "confidence": ...} wrapper
+// and a bare value
+func (f *FieldValue[T]) UnmarshalJSON(data []byte) error {
+	if bytes.HasPrefix(bytes.TrimSpace(data), []byte("{")) {
+		var raw map[string]json.RawMessage
+		if err := json.Unmarshal(data, &raw); err == nil {
+			_, hasValue := raw["value"]
+			_, hasConfidence := raw["confidence"]
+			if hasValue && hasConfidence && len(raw) == 2 {
+				var w fieldValueJSON[T]
+				if err := json.Unmarshal(data, &w); err != nil {
+					return err
+				}
+				*f = FieldValue[T]{Value: w.Value, Confidence: w.Confidence}
+				return nil
+			}
+		}
+	}
+
+	var v T
+	if err := json.Unmarshal(data, &v); err != nil {
+		return err
+	}
+	*f = FieldValue[T]{Value: v, Confidence: 1}
+	return nil
+}
+
+// MarshalJSON encodes the value in the wrapper form the server returns
+func (f FieldValue[T]) MarshalJSON() ([]byte, error) {
+	return json.Marshal(fieldValueJSON[T]{Value: f.Value, Confidence: f.Confidence})
+}
+
+// JSONSchemaAlias makes SchemaFor describe the field as a plain T
+func (FieldValue[T]) JSONSchemaAlias() any {
+	return new(T)
+}
+
+// stripConfidence replaces every {"value", "confidence"} wrapper in a generically
+// decoded result with its value, so it can be checked against the plain schema
+func stripConfidence(v interface{}) interface{} {
+	raw, err := json.Marshal(v)
+	if err != nil {
+		return v
+	}
+	var data interface{}
+	if err := json.Unmarshal(raw, &data); err != nil {
+		return v
+	}
+	return unwrapConfidence(data)
+}
+
+func unwrapConfidence(v interface{}) interface{} {
+	switch val := v.(type) {
+	case map[string]interface{}:
+		if _, ok := val["confidence"].(float64); ok && len(val) == 2 {
+			if inner, ok := val["value"]; ok {
+				return unwrapConfidence(inner)
+			}
+		}
+		for k, x := range val {
+			val[k] = unwrapConfidence(x)
+		}
+	case []interface{}:
+		for i, x := range val {
+			val[i] = unwrapConfidence(x)
+		}
+	}
+	return v
+}
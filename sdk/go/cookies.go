@@ -0,0 +1,104 @@
+package scrapeapi
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Cookie is sent to the target website, e.g. to scrape pages behind a login
+type Cookie struct {
+	Name   string `json:"name"`
+	Value  string `json:"value"`
+	Domain string `json:"domain,omitempty"`
+	Path   string `json:"path,omitempty"`
+	// Expires is when the cookie expires; nil for a session cookie
+	Expires  *time.Time `json:"expires,omitempty"`
+	Secure   bool       `json:"secure,omitempty"`
+	HTTPOnly bool       `json:"http_only,omitempty"`
+}
+
+// CookiesFromJar returns the cookies jar would send to u.
+// A jar does not expose cookie attributes, so the cookies are scoped to u's host and path "/"
+// and Secure is left unset.
+func CookiesFromJar(jar http.CookieJar, u *url.URL) []Cookie {
+	httpCookies := jar.Cookies(u)
+	cookies := make([]Cookie, 0, len(httpCookies))
+	for _, hc := range httpCookies {
+		cookies = append(cookies, Cookie{
+			Name:   hc.Name,
+			Value:  hc.Value,
+			Domain: u.Hostname(),
+			Path:   "/",
+		})
+	}
+	return cookies
+}
+
+// LoadCookiesTxt reads cookies from a Netscape cookies.txt file, as exported by browsers and curl
+func LoadCookiesTxt(path string) ([]Cookie, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("open cookies file: %w", err)
+	}
+	defer f.Close()
+
+	return ParseCookiesTxt(f)
+}
+
+// httpOnlyPrefix marks HttpOnly cookies in cookies.txt files
+const httpOnlyPrefix = "#HttpOnly_"
+
+// ParseCookiesTxt parses cookies in Netscape cookies.txt format: one cookie per line
+// with the tab-separated fields domain, include-subdomains, path, secure, expiry, name and value
+func ParseCookiesTxt(r io.Reader) ([]Cookie, error) {
+	var cookies []Cookie
+	scanner := bufio.NewScanner(r)
+	for lineNo := 1; scanner.Scan(); lineNo++ {
+		line := strings.TrimRight(scanner.Text(), "\r")
+
+		httpOnly := strings.HasPrefix(line, httpOnlyPrefix)
+		if httpOnly {
+			line = strings.TrimPrefix(line, httpOnlyPrefix)
+		}
+		if strings.TrimSpace(line) == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		fields := strings.Split(line, "\t")
+		if len(fields) != 7 {
+			return nil, fmt.Errorf("cookies line %d: expected 7 tab-separated fields, got %d", lineNo, len(fields))
+		}
+
+		c := Cookie{
+			Domain:   fields[0],
+			Path:     fields[2],
+			Secure:   strings.EqualFold(fields[3], "TRUE"),
+			Name:     fields[5],
+			Value:    fields[6],
+			HTTPOnly: httpOnly,
+		}
+
+		expiry, err := strconv.ParseInt(fields[4], 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("cookies line %d: invalid expiry %q", lineNo, fields[4])
+		}
+		if expiry > 0 {
+			t := time.Unix(expiry, 0).UTC()
+			c.Expires = &t
+		}
+
+		cookies = append(cookies, c)
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("read cookies: %w", err)
+	}
+	return cookies, nil
+}
@@ -0,0 +1,109 @@
+package scrapeapi
+
+import (
+	"context"
+	"iter"
+	"sort"
+)
+
+// CrawlConfig controls how far the crawl graph follows links from WebsiteURL
+type CrawlConfig struct {
+	// MaxDepth is the number of link hops followed from the start URL; the server default applies when zero
+	MaxDepth int `json:"max_depth,omitempty"`
+	// Allow keeps only links matching at least one of these regular expressions
+	Allow []string `json:"allow,omitempty"`
+	// Deny drops links matching any of these regular expressions
+	Deny []string `json:"deny,omitempty"`
+	// SameDomain stays on the host of the start URL
+	SameDomain bool `json:"same_domain,omitempty"`
+	// MaxPages is the page budget of the crawl; the server default applies when zero
+	MaxPages int `json:"max_pages,omitempty"`
+}
+
+func (c *CrawlConfig) validate(v *validator, field string) {
+	v.checkPatterns(field+".allow", c.Allow)
+	v.checkPatterns(field+".deny", c.Deny)
+	if c.MaxDepth < 0 {
+		v.addf(field+".max_depth", "must not be negative, got %d", c.MaxDepth)
+	}
+	if c.MaxPages < 0 {
+		v.addf(field+".max_pages", "must not be negative, got %d", c.MaxPages)
+	}
+}
+
+// HostRateLimit caps how hard a crawl or sitemap job hits each target host
+type HostRateLimit struct {
+	// RequestsPerSecond is the maximum request rate per host; the server default applies when zero
+	RequestsPerSecond float64 `json:"requests_per_second,omitempty"`
+	// Concurrency is the maximum number of requests in flight per host; the server default applies when zero
+	Concurrency int `json:"concurrency,omitempty"`
+}
+
+func (l *HostRateLimit) validate(v *validator, field string) {
+	if l.RequestsPerSecond < 0 {
+		v.addf(field+".requests_per_second", "must not be negative, got %g", l.RequestsPerSecond)
+	}
+	if l.Concurrency < 0 {
+		v.addf(field+".concurrency", "must not be negative, got %d", l.Concurrency)
+	}
+}
+
+// CrawlPages waits for a crawl or sitemap job and yields every page as soon as
+// it finishes, in whatever order the server completes them. A failed job ends
+// the iteration with its error after the pages it did finish. Breaking out of
+// the loop stops watching; the job keeps running unless WithCancelOnContextDone is given.
+//
+//	for page, err := range client.CrawlPages(ctx, resp.RequestID, scrapeapi.WithStreaming()) {
+//		if err != nil {
+//			return err
+//		}
+//		fmt.Println(page.URL, page.Status)
+//	}
+func (c *Client) CrawlPages(ctx context.Context, requestID string, opts ...WaitOption) iter.Seq2[PageResult, error] {
+	return func(yield func(PageResult, error) bool) {
+		ctx, cancel := context.WithCancel(ctx)
+		defer cancel()
+
+		seen := map[string]bool{}
+		stopped := false
+		emit := func(resp *ScrapeResponse) {
+			if stopped {
+				return
+			}
+			urls := make([]string, 0, len(resp.Pages))
+			for url, page := range resp.Pages {
+				if page.Status.IsTerminal() && !seen[url] {
+					urls = append(urls, url)
+				}
+			}
+			sort.Strings(urls)
+			for _, url := range urls {
+				seen[url] = true
+				page := resp.Pages[url]
+				if page.URL == "" {
+					page.URL = url
+				}
+				if !yield(page, nil) {
+					stopped = true
+					cancel()
+					return
+				}
+			}
+		}
+
+		opts = append(opts[:len(opts):len(opts)], func(cfg *waitConfig) {
+			prev := cfg.onProgress
+			cfg.onProgress = func(resp *ScrapeResponse) {
+				if prev != nil {
+					prev(resp)
+				}
+				emit(resp)
+			}
+		})
+
+		_, err := c.WaitForCompletion(ctx, requestID, 0, opts...)
+		if err != nil && !stopped {
+			yield(PageResult{}, err)
+		}
+	}
+}
@@ -0,0 +1,76 @@
+package scrapeapi
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"sync"
+)
+
+// WithDebugDump writes every JSON API call, such as StartScrape and GetScrape,
+// to w with full headers and bodies, for diagnosing what the server actually
+// receives. API keys, passwords, tokens, cookies and credential headers are
+// masked. Downloads and event streams are not dumped.
+func WithDebugDump(w io.Writer) ClientOption {
+	return func(c *Client) {
+		if w == nil {
+			c.debugDump = nil
+			return
+		}
+		c.debugDump = &debugDumper{w: w}
+	}
+}
+
+// debugDumper serializes dumps of concurrent calls; a nil dumper does nothing
+type debugDumper struct {
+	mu sync.Mutex
+	w  io.Writer
+}
+
+func (d *debugDumper) request(req *http.Request, body []byte) {
+	if d == nil {
+		return
+	}
+	d.write(fmt.Sprintf(">>> %s %s", req.Method, req.URL), req.Header, body)
+}
+
+func (d *debugDumper) response(req *http.Request, status string, header http.Header, body []byte) {
+	if d == nil {
+		return
+	}
+	d.write(fmt.Sprintf("<<< %s %s: %s", req.Method, req.URL.Path, status), header, body)
+}
+
+func (d *debugDumper) write(first string, header http.Header, body []byte) {
+	var buf bytes.Buffer
+	buf.WriteString(first + "\n")
+
+	header = redactHeader(header)
+	keys := make([]string, 0, len(header))
+	for key := range header {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		for _, value := range header[key] {
+			fmt.Fprintf(&buf, "%s: %s\n", key, value)
+		}
+	}
+
+	if len(body) > 0 {
+		buf.WriteString("\n")
+		body = redactJSON(body)
+		if err := json.Indent(&buf, body, "", "  "); err != nil {
+			buf.Write(body)
+		}
+		buf.WriteString("\n")
+	}
+	buf.WriteString("\n")
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	_, _ = d.w.Write(buf.Bytes())
+}
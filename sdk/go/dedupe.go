@@ -0,0 +1,25 @@
+package scrapeapi
+
+import "fmt"
+
+// DedupeConfig makes the server merge duplicate items across the sources and
+// pages of a job before returning the result
+type DedupeConfig struct {
+	// KeyFields are the item fields compared to detect duplicates, e.g. ["title", "company"];
+	// all fields are compared when empty
+	KeyFields []string `json:"key_fields,omitempty"`
+	// Threshold is the similarity from 0 to 1 at which key values count as equal;
+	// zero requires an exact match
+	Threshold float64 `json:"threshold,omitempty"`
+}
+
+func (d *DedupeConfig) validate(v *validator, field string) {
+	for i, k := range d.KeyFields {
+		if k == "" {
+			v.addf(fmt.Sprintf("%s.key_fields[%d]", field, i), "must not be empty")
+		}
+	}
+	if d.Threshold < 0 || d.Threshold > 1 {
+		v.addf(field+".threshold", "must be between 0 and 1, got %g", d.Threshold)
+	}
+}
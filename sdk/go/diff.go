@@ -0,0 +1,273 @@
+package scrapeapi
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// ResultDiff is the structured difference between the results of two jobs,
+// e.g. two runs of the same monitoring scrape
+type ResultDiff struct {
+	// Added are items only in the newer result
+	Added []interface{}
+	// Removed are items only in the older result
+	Removed []interface{}
+	// Changed are items present in both under the same key but with different values
+	Changed []ItemChange
+}
+
+// IsEmpty reports whether the results were identical
+func (d *ResultDiff) IsEmpty() bool {
+	return len(d.Added) == 0 && len(d.Removed) == 0 && len(d.Changed) == 0
+}
+
+// ItemChange is an item whose fields changed between two results
+type ItemChange struct {
+	Key    string
+	Before interface{}
+	After  interface{}
+	// Fields names the top-level fields that differ, sorted
+	Fields []string
+}
+
+// DiffOption is a functional option for DiffScrapes and DiffResults
+type DiffOption func(*diffConfig)
+
+type diffConfig struct {
+	key   string
+	items string
+}
+
+// WithDiffKey matches items of the two results by the value of field, so
+// modified items are reported as changes instead of a removal plus an addition
+func WithDiffKey(field string) DiffOption {
+	return func(cfg *diffConfig) {
+		cfg.key = field
+	}
+}
+
+// WithDiffItems locates the list of items in the result by a dotted path,
+// e.g. "jobs" or "data.listings". By default the result itself is used if it
+// is a list, then its only list-valued field, and otherwise the whole result is one item.
+func WithDiffItems(path string) DiffOption {
+	return func(cfg *diffConfig) {
+		cfg.items = path
+	}
+}
+
+// DiffScrapes fetches two jobs and compares their results, treating
+// requestIDA as the older and requestIDB as the newer one
+func (c *Client) DiffScrapes(ctx context.Context, requestIDA, requestIDB string, opts ...DiffOption) (*ResultDiff, error) {
+	// Create a span for this operation
+	// If there's no existing span in context, this creates a new root span
+	ctx, span := c.tracer.Start(ctx, "scrapeapi.DiffScrapes")
+	defer span.End()
+
+	a, err := c.GetScrape(ctx, requestIDA)
+	if err != nil {
+		return nil, err
+	}
+	b, err := c.GetScrape(ctx, requestIDB)
+	if err != nil {
+		return nil, err
+	}
+
+	return DiffResults(a, b, opts...)
+}
+
+// DiffResults compares the results of two job responses, a being the older one
+func DiffResults(a, b *ScrapeResponse, opts ...DiffOption) (*ResultDiff, error) {
+	cfg := &diffConfig{}
+	for _, opt := range opts {
+		opt(cfg)
+	}
+
+	before, err := cfg.itemsOf(a)
+	if err != nil {
+		return nil, err
+	}
+	after, err := cfg.itemsOf(b)
+	if err != nil {
+		return nil, err
+	}
+
+	if cfg.key == "" {
+		return diffUnkeyed(before, after), nil
+	}
+	return diffKeyed(cfg.key, before, after)
+}
+
+// itemsOf returns the list of items in the result of resp
+func (cfg *diffConfig) itemsOf(resp *ScrapeResponse) ([]interface{}, error) {
+	if resp.Status != StatusCompleted {
+		return nil, fmt.Errorf("diff: job %s is %s, not completed", resp.RequestID, resp.Status)
+	}
+
+	data := resultData(resp.Result)
+	// Normalize typed values to the generic JSON representation
+	if raw, err := json.Marshal(data); err == nil {
+		_ = json.Unmarshal(raw, &data)
+	}
+
+	if cfg.items != "" {
+		node := data
+		for _, part := range strings.Split(cfg.items, ".") {
+			m, ok := node.(map[string]interface{})
+			if !ok {
+				return nil, fmt.Errorf("diff: result of %s has no %q", resp.RequestID, cfg.items)
+			}
+			node = m[part]
+		}
+		items, ok := node.([]interface{})
+		if !ok {
+			return nil, fmt.Errorf("diff: %q in result of %s is %s, not a list", cfg.items, resp.RequestID, jsonType(node))
+		}
+		return items, nil
+	}
+
+	switch val := data.(type) {
+	case nil:
+		return nil, nil
+	case []interface{}:
+		return val, nil
+	case map[string]interface{}:
+		var lists [][]interface{}
+		for _, field := range val {
+			if list, ok := field.([]interface{}); ok {
+				lists = append(lists, list)
+			}
+		}
+		if len(lists) == 1 {
+			return lists[0], nil
+		}
+	}
+	return []interface{}{data}, nil
+}
+
+// diffUnkeyed compares items by value only
+func diffUnkeyed(before, after []interface{}) *ResultDiff {
+	diff := &ResultDiff{}
+
+	remaining := map[string]int{}
+	for _, item := range before {
+		remaining[canonicalJSON(item)]++
+	}
+	for _, item := range after {
+		k := canonicalJSON(item)
+		if remaining[k] > 0 {
+			remaining[k]--
+			continue
+		}
+		diff.Added = append(diff.Added, item)
+	}
+	for _, item := range before {
+		k := canonicalJSON(item)
+		if remaining[k] > 0 {
+			remaining[k]--
+			diff.Removed = append(diff.Removed, item)
+		}
+	}
+
+	return diff
+}
+
+// diffKeyed matches items by the value of their key field
+func diffKeyed(key string, before, after []interface{}) (*ResultDiff, error) {
+	diff := &ResultDiff{}
+
+	old, err := indexByKey(key, before)
+	if err != nil {
+		return nil, err
+	}
+	seen := map[string]bool{}
+
+	for i, item := range after {
+		k, err := itemKey(key, item)
+		if err != nil {
+			return nil, fmt.Errorf("diff: newer item %d: %w", i, err)
+		}
+		if seen[k] {
+			return nil, fmt.Errorf("diff: newer result has duplicate %s %q", key, k)
+		}
+		seen[k] = true
+
+		prev, ok := old[k]
+		switch {
+		case !ok:
+			diff.Added = append(diff.Added, item)
+		case !reflect.DeepEqual(prev, item):
+			diff.Changed = append(diff.Changed, ItemChange{Key: k, Before: prev, After: item, Fields: changedFields(prev, item)})
+		}
+	}
+	for _, item := range before {
+		k, _ := itemKey(key, item)
+		if !seen[k] {
+			diff.Removed = append(diff.Removed, item)
+		}
+	}
+
+	return diff, nil
+}
+
+func indexByKey(key string, items []interface{}) (map[string]interface{}, error) {
+	index := make(map[string]interface{}, len(items))
+	for i, item := range items {
+		k, err := itemKey(key, item)
+		if err != nil {
+			return nil, fmt.Errorf("diff: older item %d: %w", i, err)
+		}
+		if _, dup := index[k]; dup {
+			return nil, fmt.Errorf("diff: older result has duplicate %s %q", key, k)
+		}
+		index[k] = item
+	}
+	return index, nil
+}
+
+func itemKey(key string, item interface{}) (string, error) {
+	obj, ok := item.(map[string]interface{})
+	if !ok {
+		return "", fmt.Errorf("is %s, not an object", jsonType(item))
+	}
+	v, ok := obj[key]
+	if !ok || v == nil {
+		return "", fmt.Errorf("has no %q field", key)
+	}
+	if s, ok := v.(string); ok {
+		return s, nil
+	}
+	return canonicalJSON(v), nil
+}
+
+// changedFields lists the top-level fields that differ between two items
+func changedFields(before, after interface{}) []string {
+	a, okA := before.(map[string]interface{})
+	b, okB := after.(map[string]interface{})
+	if !okA || !okB {
+		return nil
+	}
+
+	var fields []string
+	for k, v := range a {
+		if w, ok := b[k]; !ok || !reflect.DeepEqual(v, w) {
+			fields = append(fields, k)
+		}
+	}
+	for k := range b {
+		if _, ok := a[k]; !ok {
+			fields = append(fields, k)
+		}
+	}
+	sort.Strings(fields)
+	return fields
+}
+
+// canonicalJSON encodes v with sorted object keys, for comparing generic values
+func canonicalJSON(v interface{}) string {
+	raw, _ := json.Marshal(v)
+	return string(raw)
+}
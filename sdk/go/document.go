@@ -0,0 +1,32 @@
+package scrapeapi
+
+// DocumentType is the file format of a document source
+type DocumentType string
+
+// Document formats the API extracts text from
+const (
+	DocumentPDF  DocumentType = "pdf"
+	DocumentDOCX DocumentType = "docx"
+)
+
+// DocumentSource is a PDF or DOCX file the server downloads and extracts text
+// from before running the prompt and schema, as it would for an HTML page
+type DocumentSource struct {
+	URL string `json:"url"`
+	// Type overrides format detection from the URL and Content-Type, e.g. for download links without an extension
+	Type DocumentType `json:"type,omitempty"`
+	// Pages limits extraction to a page range such as "1-5" or "2,4,7"; all pages when empty
+	Pages string `json:"pages,omitempty"`
+}
+
+func (d *DocumentSource) validate(v *validator, field string) {
+	v.checkURL(field+".url", d.URL)
+	switch d.Type {
+	case "", DocumentPDF, DocumentDOCX:
+	default:
+		v.addf(field+".type", "unknown document type %q", d.Type)
+	}
+	if d.Pages != "" && d.Type == DocumentDOCX {
+		v.addf(field+".pages", "is only supported for pdf documents")
+	}
+}
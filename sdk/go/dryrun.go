@@ -0,0 +1,266 @@
+package scrapeapi
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// DryRunResult is the outcome of checking a request without running it
+type DryRunResult struct {
+	// Problems lists everything that would make the request fail, including
+	// problems with its OutputSchema; empty when it would be accepted
+	Problems []*FieldError
+	// EstimatedCostUSD and EstimatedTokens are the server's estimate of the
+	// job's LLM usage; nil when the server gave none
+	EstimatedCostUSD *float64
+	EstimatedTokens  *int
+	// Config is the request as it would run, after client defaults such as
+	// SetDefaultLLM and, when the server checked it, server defaults
+	Config *ScrapeRequest
+	// Local is true when the server has no validate endpoint and only the
+	// client-side checks ran
+	Local bool
+}
+
+// Valid reports whether the request would be accepted
+func (r *DryRunResult) Valid() bool {
+	return len(r.Problems) == 0
+}
+
+// Err returns the problems as a *ValidationError, or nil if there are none
+func (r *DryRunResult) Err() error {
+	if r.Valid() {
+		return nil
+	}
+	return &ValidationError{Errors: r.Problems}
+}
+
+// dryRunResponse is the body returned by POST /v1/scrape/validate
+type dryRunResponse struct {
+	Problems []struct {
+		Field   string `json:"field"`
+		Message string `json:"message"`
+	} `json:"problems"`
+	EstimatedCostUSD *float64       `json:"estimated_cost_usd"`
+	EstimatedTokens  *int           `json:"estimated_tokens"`
+	EffectiveConfig  *ScrapeRequest `json:"effective_config"`
+}
+
+// DryRun checks req without starting a job, e.g. in CI to catch broken
+// scrape definitions before they are deployed. It validates the request and
+// its OutputSchema locally, then asks the server's validate endpoint for its
+// own checks and a cost estimate, falling back to the local checks alone
+// when the server does not have one.
+//
+// Problems are reported in the result rather than as an error; the error is
+// only set when the check itself could not be done.
+//
+//	result, err := client.DryRun(ctx, req)
+//	if err != nil {
+//		return err
+//	}
+//	if err := result.Err(); err != nil {
+//		log.Fatalf("broken scrape definition: %v", err)
+//	}
+func (c *Client) DryRun(ctx context.Context, req *ScrapeRequest, opts ...RequestOption) (*DryRunResult, error) {
+	// Create a span for this operation
+	// If there's no existing span in context, this creates a new root span
+	ctx, span := c.tracer.Start(ctx, "scrapeapi.DryRun")
+	defer span.End()
+
+	result := &DryRunResult{Config: req}
+	resolved, err := c.resolveLLM(req)
+	if err != nil {
+		result.Problems = append(result.Problems, &FieldError{Field: "llm_alias", Message: err.Error()})
+	} else {
+		result.Config = resolved
+	}
+	span.SetAttributes(requestAttributes(result.Config)...)
+
+	v := &validator{}
+	result.Config.validate(v)
+	checkOutputSchema(v, "output_schema", result.Config.OutputSchema)
+	result.Problems = append(result.Problems, v.errs...)
+
+	var server dryRunResponse
+	// Validation has no side effects, so it is safe to retry
+	err = c.do(ctx, http.MethodPost, "/v1/scrape/validate", result.Config, &server, append(opts, idempotentCall())...)
+	switch {
+	case isEndpointUnsupported(err):
+		result.Local = true
+	case err != nil:
+		return nil, err
+	default:
+		for _, p := range server.Problems {
+			result.Problems = appendProblem(result.Problems, &FieldError{Field: p.Field, Message: p.Message})
+		}
+		result.EstimatedCostUSD = server.EstimatedCostUSD
+		result.EstimatedTokens = server.EstimatedTokens
+		if server.EffectiveConfig != nil {
+			result.Config = server.EffectiveConfig
+		}
+	}
+
+	span.SetAttributes(
+		attribute.Int("scrapeapi.dry_run.problems", len(result.Problems)),
+		attribute.Bool("scrapeapi.dry_run.local", result.Local),
+	)
+	return result, nil
+}
+
+// isEndpointUnsupported reports whether err means the server lacks the endpoint
+func isEndpointUnsupported(err error) bool {
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) {
+		return false
+	}
+	switch apiErr.StatusCode {
+	case http.StatusNotFound, http.StatusMethodNotAllowed, http.StatusNotImplemented:
+		return true
+	}
+	return false
+}
+
+// appendProblem appends fe unless an identical problem is already listed,
+// since the server repeats most of the client-side checks
+func appendProblem(problems []*FieldError, fe *FieldError) []*FieldError {
+	for _, p := range problems {
+		if p.Field == fe.Field && p.Message == fe.Message {
+			return problems
+		}
+	}
+	return append(problems, fe)
+}
+
+// jsonSchemaTypes are the type names JSON Schema defines
+var jsonSchemaTypes = map[string]bool{
+	"object": true, "array": true, "string": true, "number": true,
+	"integer": true, "boolean": true, "null": true,
+}
+
+// checkOutputSchema reports structural mistakes in a JSON Schema: unknown
+// types, malformed properties and items, required fields that are not
+// properties and local $refs that do not resolve. Example strings are not checked.
+func checkOutputSchema(v *validator, field string, schema interface{}) {
+	if schema == nil {
+		return
+	}
+	if _, ok := schema.(string); ok {
+		return
+	}
+	raw, err := json.Marshal(schema)
+	if err != nil {
+		v.addf(field, "cannot be marshaled: %v", err)
+		return
+	}
+	var root map[string]interface{}
+	if err := json.Unmarshal(raw, &root); err != nil {
+		v.addf(field, "must be a JSON Schema object or an example string")
+		return
+	}
+
+	var walk func(path string, node map[string]interface{})
+	walk = func(path string, node map[string]interface{}) {
+		switch t := node["type"].(type) {
+		case nil, string, []interface{}:
+			for _, name := range schemaTypes(node) {
+				if !jsonSchemaTypes[name] {
+					v.addf(path, "unknown type %q", name)
+				}
+			}
+		default:
+			v.addf(path, "type must be a string or a list of strings, got %v", t)
+		}
+
+		if ref, ok := node["$ref"].(string); ok && strings.HasPrefix(ref, "#") && lookupRef(root, ref) == nil {
+			v.addf(path, "$ref %q does not resolve", ref)
+		}
+
+		props, hasProps := node["properties"].(map[string]interface{})
+		if _, ok := node["properties"]; ok && !hasProps {
+			v.addf(path+".properties", "must be an object")
+		}
+		for _, name := range sortedKeys(props) {
+			child, ok := props[name].(map[string]interface{})
+			if !ok {
+				v.addf(path+".properties."+name, "must be a schema object")
+				continue
+			}
+			walk(path+".properties."+name, child)
+		}
+
+		if required, ok := node["required"]; ok {
+			names, ok := required.([]interface{})
+			if !ok {
+				v.addf(path+".required", "must be a list of property names")
+			}
+			for _, n := range names {
+				name, _ := n.(string)
+				if _, ok := props[name]; !ok && hasProps {
+					v.addf(path+".required", "names %q, which is not a property", n)
+				}
+			}
+		}
+
+		if items, ok := node["items"]; ok {
+			child, ok := items.(map[string]interface{})
+			if !ok {
+				v.addf(path+".items", "must be a schema object")
+			} else {
+				walk(path+".items", child)
+			}
+		}
+
+		for _, key := range []string{"$defs", "definitions"} {
+			defs, _ := node[key].(map[string]interface{})
+			for _, name := range sortedKeys(defs) {
+				if child, ok := defs[name].(map[string]interface{}); ok {
+					walk(path+"."+key+"."+name, child)
+				}
+			}
+		}
+		for _, key := range []string{"anyOf", "oneOf", "allOf"} {
+			branches, _ := node[key].([]interface{})
+			for i, b := range branches {
+				if child, ok := b.(map[string]interface{}); ok {
+					walk(fmt.Sprintf("%s.%s[%d]", path, key, i), child)
+				}
+			}
+		}
+	}
+	walk(field, root)
+}
+
+// sortedKeys returns the keys of m in order, so problems are reported deterministically
+func sortedKeys(m map[string]interface{}) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// lookupRef resolves a local "#/..." reference within root
+func lookupRef(root map[string]interface{}, ref string) map[string]interface{} {
+	var node interface{} = root
+	for _, part := range strings.Split(strings.TrimPrefix(ref, "#"), "/") {
+		if part == "" {
+			continue
+		}
+		m, ok := node.(map[string]interface{})
+		if !ok {
+			return nil
+		}
+		node = m[part]
+	}
+	m, _ := node.(map[string]interface{})
+	return m
+}
@@ -0,0 +1,19 @@
+package scrapeapi
+
+// EmbeddingsConfig configures the embedding model graphs use to retrieve the
+// relevant chunks of long pages before extraction. Unset fields fall back to
+// the server defaults.
+type EmbeddingsConfig struct {
+	// Model is the embedding model, e.g. "openai/text-embedding-3-small"
+	Model    string `json:"model,omitempty"`
+	Provider string `json:"provider,omitempty"`
+	APIKey   string `json:"api_key,omitempty"`
+	// Dimensions truncates the embeddings of models that support it; the model default applies when zero
+	Dimensions int `json:"dimensions,omitempty"`
+}
+
+func (e *EmbeddingsConfig) validate(v *validator, field string) {
+	if e.Dimensions < 0 {
+		v.addf(field+".dimensions", "must not be negative, got %d", e.Dimensions)
+	}
+}
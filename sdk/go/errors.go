@@ -0,0 +1,186 @@
+package scrapeapi
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// maxErrorBody caps how much of an error response body is kept on APIError
+const maxErrorBody = 1 << 20
+
+// APIError is returned by client methods when the API responds with a non-2xx status.
+// Use errors.As to inspect it:
+//
+//	var apiErr *scrapeapi.APIError
+//	if errors.As(err, &apiErr) && apiErr.StatusCode == http.StatusNotFound {
+//		// ...
+//	}
+type APIError struct {
+	// StatusCode is the HTTP status code, e.g. 400
+	StatusCode int
+	// Status is the HTTP status line, e.g. "400 Bad Request"
+	Status string
+	// Code is the machine-readable error code reported by the server, if any
+	Code string
+	// Message is the human-readable error message reported by the server, if any
+	Message string
+	// RequestID identifies the failed request or job, taken from the X-Request-ID header or the body
+	RequestID string
+	// RetryAfter is the delay requested by the server via the Retry-After header, if any
+	RetryAfter time.Duration
+	// Body is the raw response body
+	Body []byte
+}
+
+func (e *APIError) Error() string {
+	var b strings.Builder
+	b.WriteString("API error: ")
+	b.WriteString(e.Status)
+	if e.Code != "" {
+		fmt.Fprintf(&b, " [%s]", e.Code)
+	}
+	if e.Message != "" {
+		b.WriteString(": ")
+		b.WriteString(e.Message)
+	}
+	if e.RequestID != "" {
+		fmt.Fprintf(&b, " (request_id=%s)", e.RequestID)
+	}
+	return b.String()
+}
+
+// ErrWaitTimeout matches, via errors.Is, the *WaitTimeoutError returned when
+// a WithMaxWait or WithDeadline limit passes before the job finishes
+var ErrWaitTimeout = errors.New("wait timed out")
+
+// WaitTimeoutError is returned when waiting for a job exceeds its limit
+type WaitTimeoutError struct {
+	RequestID string
+	// LastStatus is the last status observed, empty if the job was never polled successfully
+	LastStatus Status
+	// LastResponse is the last job state observed, if any
+	LastResponse *ScrapeResponse
+}
+
+func (e *WaitTimeoutError) Error() string {
+	if e.LastStatus == "" {
+		return fmt.Sprintf("wait for %s timed out", e.RequestID)
+	}
+	return fmt.Sprintf("wait for %s timed out in status %s", e.RequestID, e.LastStatus)
+}
+
+// Is makes errors.Is(err, ErrWaitTimeout) match
+func (e *WaitTimeoutError) Is(target error) bool {
+	return target == ErrWaitTimeout
+}
+
+// JobErrorCode is the machine-readable reason a job failed, reported in ScrapeResponse.ErrorCode
+type JobErrorCode string
+
+// Job error codes reported by the API
+const (
+	// ErrorCodeCaptchaUnsolved means the target page showed a captcha that could not be solved in time
+	ErrorCodeCaptchaUnsolved JobErrorCode = "captcha_unsolved"
+	// ErrorCodeBudgetExceeded means the job hit its MaxCostUSD or MaxTokens cap
+	ErrorCodeBudgetExceeded JobErrorCode = "budget_exceeded"
+)
+
+// JobError is returned by WaitForCompletion and ScrapeAndWait when the job
+// itself fails or is canceled, as opposed to the API call failing
+type JobError struct {
+	RequestID string
+	// Status is StatusFailed or StatusCanceled
+	Status  Status
+	Code    JobErrorCode
+	Message string
+}
+
+func (e *JobError) Error() string {
+	var b strings.Builder
+	if e.Status == StatusCanceled {
+		b.WriteString("scraping canceled")
+	} else {
+		b.WriteString("scraping failed")
+	}
+	if e.Code != "" {
+		fmt.Fprintf(&b, " [%s]", e.Code)
+	}
+	b.WriteString(": ")
+	b.WriteString(e.Message)
+	return b.String()
+}
+
+// newJobError builds the JobError of a failed or canceled job
+func newJobError(resp *ScrapeResponse) *JobError {
+	return &JobError{RequestID: resp.RequestID, Status: resp.Status, Code: resp.ErrorCode, Message: resp.Error}
+}
+
+// errorBody covers the error payload shapes the API may return:
+// FastAPI's {"detail": ...} as well as {"code", "message"} and {"error": {...}}
+type errorBody struct {
+	Detail    json.RawMessage `json:"detail"`
+	Code      string          `json:"code"`
+	Message   string          `json:"message"`
+	RequestID string          `json:"request_id"`
+	Error     json.RawMessage `json:"error"`
+}
+
+// newAPIError builds an APIError from a non-2xx response, reading its body
+func newAPIError(resp *http.Response) *APIError {
+	apiErr := &APIError{
+		StatusCode: resp.StatusCode,
+		Status:     resp.Status,
+		RequestID:  resp.Header.Get("X-Request-ID"),
+		RetryAfter: retryAfter(resp.Header),
+	}
+
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, maxErrorBody))
+	apiErr.Body = body
+
+	var eb errorBody
+	if err := json.Unmarshal(body, &eb); err != nil {
+		apiErr.Message = strings.TrimSpace(string(body))
+		return apiErr
+	}
+
+	apiErr.Code = eb.Code
+	apiErr.Message = eb.Message
+	if apiErr.RequestID == "" {
+		apiErr.RequestID = eb.RequestID
+	}
+
+	if len(eb.Error) > 0 {
+		var nested struct {
+			Code    string `json:"code"`
+			Message string `json:"message"`
+		}
+		var msg string
+		if err := json.Unmarshal(eb.Error, &nested); err == nil {
+			if apiErr.Code == "" {
+				apiErr.Code = nested.Code
+			}
+			if apiErr.Message == "" {
+				apiErr.Message = nested.Message
+			}
+		} else if err := json.Unmarshal(eb.Error, &msg); err == nil && apiErr.Message == "" {
+			apiErr.Message = msg
+		}
+	}
+
+	if apiErr.Message == "" && len(eb.Detail) > 0 {
+		var detail string
+		if err := json.Unmarshal(eb.Detail, &detail); err == nil {
+			apiErr.Message = detail
+		} else {
+			// Validation errors carry a list of objects; keep them verbatim
+			apiErr.Message = string(eb.Detail)
+		}
+	}
+
+	return apiErr
+}
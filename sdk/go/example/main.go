@@ -9,7 +9,6 @@ import (
 	"time"
 
 	scrapeapi "github.com/dir01/scrapeapi/sdk/go"
-	jsonschema "github.com/invopop/jsonschema"
 	"github.com/joho/godotenv"
 	"go.opentelemetry.io/otel"
 	"go.opentelemetry.io/otel/propagation"
@@ -18,18 +17,18 @@ import (
 )
 
 type ParsedJob struct {
-	Title           string `json:"title" jsonschema:"title"`
-	CompanyName     string `json:"company_name" jsonschema:"company_name"`
-	Location        string `json:"location" jsonschema:"location, description=Where to company is headquatered. This is not necessary where the potential employee must reside"`
-	IsFeatured      bool   `json:"is_featured" jsonschema:"is_featured"`
-	CommitmentType  string `json:"commitment_type" jsonschema:"commitment_type,enum=part_time,enum=full_type,enum=freelance,enum=contract,enum=intern"`
-	Salary          string `json:"salary" jsonschema:"free-form description of salary expectations"`
-	GeoRestrictions string `json:"geo_restrictions" jsonschema:"geo_restrictions,example=Anywhere in the world,example=USA,example=Argentina|Mexico|Colombia"`
-	Age             string `json:"age" jsonschema:"age,description=How long ago was job posted,example=new,example=1d,example=8d`
+	Title           string `json:"title"`
+	CompanyName     string `json:"company_name"`
+	Location        string `json:"location" jsonschema:"description=Where the company is headquartered. This is not necessarily where the potential employee must reside"`
+	IsFeatured      bool   `json:"is_featured"`
+	CommitmentType  string `json:"commitment_type" jsonschema:"enum=part_time,enum=full_time,enum=freelance,enum=contract,enum=intern"`
+	Salary          string `json:"salary"`
+	GeoRestrictions string `json:"geo_restrictions" jsonschema:"example=Anywhere in the world,example=USA,example=Argentina|Mexico|Colombia"`
+	Age             string `json:"age"`
 }
 
 type ParsedJobsResponse struct {
-	Jobs []ParsedJob `json:"jobs" jsonschema:"jobs"`
+	Jobs []ParsedJob `json:"jobs"`
 }
 
 func main() {
@@ -69,12 +68,19 @@ func main() {
 	client := scrapeapi.NewClient(baseURL)
 
 	// Example 1: Smart scraper with JSON Schema
-	schema := jsonschema.Reflect(&ParsedJobsResponse{})
+	// Catch struct tags the schema generator would silently drop
+	if err := scrapeapi.CheckSchemaTags[ParsedJobsResponse](); err != nil {
+		log.Fatal(err)
+	}
+	schema := scrapeapi.SchemaFor[ParsedJobsResponse](
+		scrapeapi.Field("jobs.salary").Describe("Free-form description of salary expectations"),
+		scrapeapi.Field("jobs.age").Describe("How long ago the job was posted").Examples("new", "1d", "8d"),
+	)
 
 	fmt.Printf("schema: %v", schema)
 
 	req := &scrapeapi.ScrapeRequest{
-		Graph:        "smart",
+		Graph:        scrapeapi.GraphSmart,
 		UserPrompt:   "This page contains a list of job offering ads. It is CRUCIAL that we get accurate information on all the jobs in the list. Please include ad title, company name, salary expectations, commitment, and geo restrictions",
 		WebsiteURL:   scrapeapi.String(targetURL),
 		OutputSchema: schema,
@@ -122,10 +128,10 @@ func main() {
 
 		fmt.Printf("Status: %s\n", pollResp.Status)
 
-		if pollResp.Status == "completed" {
+		if pollResp.Status == scrapeapi.StatusCompleted {
 			fmt.Printf("✅ Success! Result: %v\n", pollResp.Result)
 			break
-		} else if pollResp.Status == "failed" {
+		} else if pollResp.Status == scrapeapi.StatusFailed {
 			log.Fatalf("❌ Scraping failed: %s", pollResp.Error)
 		}
 
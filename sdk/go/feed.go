@@ -0,0 +1,22 @@
+package scrapeapi
+
+// FeedConfig controls how the feed graph handles the entries of an RSS or Atom feed
+type FeedConfig struct {
+	// ScrapeEntries also scrapes the page behind every entry link with the
+	// request's UserPrompt and OutputSchema, filling FeedEntry.Result
+	ScrapeEntries bool `json:"scrape_entries,omitempty"`
+	// MaxEntries keeps only the newest entries; the server default applies when zero
+	MaxEntries int `json:"max_entries,omitempty"`
+}
+
+func (f *FeedConfig) validate(v *validator, field string) {
+	if f.MaxEntries < 0 {
+		v.addf(field+".max_entries", "must not be negative, got %d", f.MaxEntries)
+	}
+}
+
+// DecodeResult decodes the scraped entry page into the value pointed to by into,
+// as strictly as ScrapeResponse.DecodeResult
+func (e *FeedEntry) DecodeResult(into interface{}) error {
+	return decodeResult(resultData(e.Result), into)
+}
@@ -0,0 +1,109 @@
+package scrapeapi
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/invopop/jsonschema"
+)
+
+// FieldSpec adds extraction instructions to one field of a generated schema.
+// Unlike jsonschema struct tags, a spec naming a field that does not exist is
+// an error rather than silently dropped.
+//
+//	schema := scrapeapi.SchemaFor[JobListings](
+//		scrapeapi.Field("jobs.salary").Describe("Yearly salary in USD").Examples("120000"),
+//		scrapeapi.Field("jobs.age").Describe("How long ago the job was posted").Examples("new", "1d", "8d"),
+//	)
+type FieldSpec struct {
+	path        string
+	description *string
+	title       *string
+	examples    []interface{}
+	enum        []interface{}
+}
+
+// Field starts a spec for the field at path, a dotted list of JSON property
+// names. Lists are traversed implicitly, so "jobs.salary" is the salary of every job.
+func Field(path string) *FieldSpec {
+	return &FieldSpec{path: path}
+}
+
+// Describe sets the description the LLM reads as the field's instructions
+func (f *FieldSpec) Describe(description string) *FieldSpec {
+	f.description = &description
+	return f
+}
+
+// Title sets the field's short human-readable name
+func (f *FieldSpec) Title(title string) *FieldSpec {
+	f.title = &title
+	return f
+}
+
+// Examples adds example values to the field
+func (f *FieldSpec) Examples(examples ...interface{}) *FieldSpec {
+	f.examples = append(f.examples, examples...)
+	return f
+}
+
+// Enum restricts the field to the given values
+func (f *FieldSpec) Enum(values ...interface{}) *FieldSpec {
+	f.enum = append(f.enum, values...)
+	return f
+}
+
+// ApplyFields applies field specs to schema, failing on the first path that
+// does not name a property of it
+func ApplyFields(schema *jsonschema.Schema, fields ...*FieldSpec) error {
+	for _, f := range fields {
+		target, err := lookupField(schema, f.path)
+		if err != nil {
+			return err
+		}
+		if f.description != nil {
+			target.Description = *f.description
+		}
+		if f.title != nil {
+			target.Title = *f.title
+		}
+		if len(f.examples) > 0 {
+			target.Examples = append(target.Examples, f.examples...)
+		}
+		if len(f.enum) > 0 {
+			target.Enum = f.enum
+		}
+	}
+	return nil
+}
+
+// lookupField resolves a dotted property path within schema, descending into list items
+func lookupField(schema *jsonschema.Schema, path string) (*jsonschema.Schema, error) {
+	node := schema
+	var walked []string
+	for _, name := range strings.Split(path, ".") {
+		for node.Properties == nil {
+			if def, ok := strings.CutPrefix(node.Ref, defsPrefix); ok && schema.Definitions[def] != nil {
+				// Recursive types are described once, in $defs
+				node = schema.Definitions[def]
+			} else if node.Items != nil {
+				node = node.Items
+			} else {
+				break
+			}
+		}
+		var child *jsonschema.Schema
+		if node.Properties != nil {
+			child, _ = node.Properties.Get(name)
+		}
+		if child == nil {
+			if len(walked) == 0 {
+				return nil, fmt.Errorf("schema field %q: schema has no property %q", path, name)
+			}
+			return nil, fmt.Errorf("schema field %q: %s has no property %q", path, strings.Join(walked, "."), name)
+		}
+		walked = append(walked, name)
+		node = child
+	}
+	return node, nil
+}
@@ -0,0 +1,6 @@
+package scrapeapi
+
+// The wire types in types_gen.go come from the component schemas of the
+// OpenAPI document at the repository root; methods on them stay in the
+// hand-written files next to their features.
+//go:generate go run ./internal/openapigen -in ../../openapi.yaml -out types_gen.go
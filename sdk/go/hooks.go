@@ -0,0 +1,65 @@
+package scrapeapi
+
+import (
+	"context"
+	"net/http"
+	"time"
+)
+
+// Hooks are callbacks for auditing and alerting on client activity.
+// Any of them may be nil. They run synchronously on the calling goroutine,
+// so they should return quickly.
+type Hooks struct {
+	// OnRequest is called before every HTTP request to the API, including retries.
+	// It may add headers but must not read the body.
+	OnRequest func(ctx context.Context, req *http.Request)
+	// OnResponse is called when an HTTP request to the API returns. resp is nil
+	// and err set if no response was received; non-2xx responses are passed as is.
+	// It must not read or close the body.
+	OnResponse func(ctx context.Context, req *http.Request, resp *http.Response, elapsed time.Duration, err error)
+	// OnJobStatusChange is called while waiting for a job, e.g. in WaitForCompletion
+	// or ScrapeAndWait, whenever its status changes. from is empty for the first update.
+	OnJobStatusChange func(ctx context.Context, from Status, resp *ScrapeResponse)
+	// OnRetry is called before a failed API call is retried after delay
+	OnRetry func(ctx context.Context, method, path string, attempt int, delay time.Duration, err error)
+}
+
+// WithHooks registers callbacks for client activity. It can be given several
+// times; hooks run in the order they were registered.
+func WithHooks(hooks Hooks) ClientOption {
+	return func(c *Client) {
+		c.hooks = append(c.hooks, hooks)
+	}
+}
+
+func (c *Client) hookRequest(ctx context.Context, req *http.Request) {
+	for _, h := range c.hooks {
+		if h.OnRequest != nil {
+			h.OnRequest(ctx, req)
+		}
+	}
+}
+
+func (c *Client) hookResponse(ctx context.Context, req *http.Request, resp *http.Response, elapsed time.Duration, err error) {
+	for _, h := range c.hooks {
+		if h.OnResponse != nil {
+			h.OnResponse(ctx, req, resp, elapsed, err)
+		}
+	}
+}
+
+func (c *Client) hookStatusChange(ctx context.Context, from Status, resp *ScrapeResponse) {
+	for _, h := range c.hooks {
+		if h.OnJobStatusChange != nil {
+			h.OnJobStatusChange(ctx, from, resp)
+		}
+	}
+}
+
+func (c *Client) hookRetry(ctx context.Context, method, path string, attempt int, delay time.Duration, err error) {
+	for _, h := range c.hooks {
+		if h.OnRetry != nil {
+			h.OnRetry(ctx, method, path, attempt, delay, err)
+		}
+	}
+}
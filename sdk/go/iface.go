@@ -0,0 +1,17 @@
+package scrapeapi
+
+import (
+	"context"
+	"time"
+)
+
+// ScrapeClient is the job lifecycle API of Client. Depend on it instead of
+// *Client to substitute a fake in tests, such as scrapeapitest.FakeClient.
+type ScrapeClient interface {
+	StartScrape(ctx context.Context, req *ScrapeRequest, opts ...RequestOption) (*ScrapeResponse, error)
+	GetScrape(ctx context.Context, requestID string, opts ...RequestOption) (*ScrapeResponse, error)
+	WaitForCompletion(ctx context.Context, requestID string, pollInterval time.Duration, opts ...WaitOption) (*ScrapeResponse, error)
+	ScrapeAndWait(ctx context.Context, req *ScrapeRequest, opts ...WaitOption) (*ScrapeResponse, error)
+}
+
+var _ ScrapeClient = (*Client)(nil)
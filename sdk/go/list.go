@@ -0,0 +1,107 @@
+package scrapeapi
+
+import (
+	"context"
+	"iter"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+// ListOptions filters and paginates ListScrapes results.
+// Zero values are not sent to the server.
+type ListOptions struct {
+	// Status returns only jobs in this status
+	Status Status
+	// Graph returns only jobs that ran this graph
+	Graph Graph
+	// CreatedAfter returns only jobs created after this time
+	CreatedAfter time.Time
+	// Tag returns only jobs carrying this tag
+	Tag string
+	// Limit caps the number of jobs per page; the server applies its own default when zero
+	Limit int
+	// Cursor continues a previous listing; use ScrapeList.NextCursor
+	Cursor string
+}
+
+func (o ListOptions) query() url.Values {
+	q := url.Values{}
+	if o.Status != "" {
+		q.Set("status", string(o.Status))
+	}
+	if o.Graph != "" {
+		q.Set("graph", string(o.Graph))
+	}
+	if !o.CreatedAfter.IsZero() {
+		q.Set("created_after", o.CreatedAfter.UTC().Format(time.RFC3339))
+	}
+	if o.Tag != "" {
+		q.Set("tag", o.Tag)
+	}
+	if o.Limit > 0 {
+		q.Set("limit", strconv.Itoa(o.Limit))
+	}
+	if o.Cursor != "" {
+		q.Set("cursor", o.Cursor)
+	}
+	return q
+}
+
+// HasMore reports whether there are more pages after this one
+func (l *ScrapeList) HasMore() bool {
+	return l.NextCursor != ""
+}
+
+// ListScrapes returns one page of scraping jobs matching opts
+func (c *Client) ListScrapes(ctx context.Context, opts ListOptions) (*ScrapeList, error) {
+	// Create a span for this operation
+	// If there's no existing span in context, this creates a new root span
+	ctx, span := c.tracer.Start(ctx, "scrapeapi.ListScrapes")
+	defer span.End()
+
+	path := "/v1/scrape"
+	if q := opts.query(); len(q) > 0 {
+		path += "?" + q.Encode()
+	}
+
+	var list ScrapeList
+	if err := c.do(ctx, http.MethodGet, path, nil, &list); err != nil {
+		return nil, err
+	}
+
+	return &list, nil
+}
+
+// AllScrapes iterates over every job matching opts, fetching pages as needed.
+// Iteration stops at the first error, which is yielded with a nil job.
+//
+//	for job, err := range client.AllScrapes(ctx, scrapeapi.ListOptions{Status: scrapeapi.StatusFailed}) {
+//		if err != nil {
+//			return err
+//		}
+//		fmt.Println(job.RequestID)
+//	}
+func (c *Client) AllScrapes(ctx context.Context, opts ListOptions) iter.Seq2[*ScrapeResponse, error] {
+	return func(yield func(*ScrapeResponse, error) bool) {
+		for {
+			list, err := c.ListScrapes(ctx, opts)
+			if err != nil {
+				yield(nil, err)
+				return
+			}
+
+			for i := range list.Items {
+				if !yield(&list.Items[i], nil) {
+					return
+				}
+			}
+
+			if !list.HasMore() {
+				return
+			}
+			opts.Cursor = list.NextCursor
+		}
+	}
+}
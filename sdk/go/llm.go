@@ -0,0 +1,241 @@
+package scrapeapi
+
+import (
+	"fmt"
+	"strings"
+)
+
+// DefaultOllamaHost is where a local Ollama server listens by default
+const DefaultOllamaHost = "http://localhost:11434"
+
+// OllamaLLM configures a model served by Ollama at host, DefaultOllamaHost
+// when empty. model is the Ollama model name, e.g. "llama3.1"; the "ollama/"
+// prefix the server routes by is added if missing. No API key is sent.
+//
+//	req.LLM = scrapeapi.OllamaLLM("", "llama3.1")
+func OllamaLLM(host, model string) *LLMConfig {
+	if host == "" {
+		host = DefaultOllamaHost
+	}
+	if !strings.HasPrefix(model, "ollama/") {
+		model = "ollama/" + model
+	}
+	return &LLMConfig{
+		Model:    model,
+		Provider: "ollama",
+		APIBase:  strings.TrimSuffix(host, "/"),
+	}
+}
+
+// AzureOpenAILLM configures an Azure OpenAI deployment. endpoint is the
+// resource URL, e.g. "https://my-resource.openai.azure.com", deployment the
+// name given to the deployed model and apiVersion the Azure API version,
+// e.g. "2024-10-21".
+func AzureOpenAILLM(endpoint, deployment, apiVersion, apiKey string) *LLMConfig {
+	return &LLMConfig{
+		Model:           "azure_openai/" + deployment,
+		Provider:        "azure_openai",
+		APIKey:          apiKey,
+		AzureEndpoint:   strings.TrimSuffix(endpoint, "/"),
+		AzureDeployment: deployment,
+		APIVersion:      apiVersion,
+	}
+}
+
+// SetDefaultLLM sets the LLM configuration used by requests that set neither
+// LLM nor LLMAlias. A nil cfg restores the server default.
+func (c *Client) SetDefaultLLM(cfg *LLMConfig) {
+	c.llmMu.Lock()
+	defer c.llmMu.Unlock()
+	c.defaultLLM = cfg.clone()
+}
+
+// SetLLMAlias registers cfg under name for requests to select with
+// ScrapeRequest.LLMAlias, so that models can be swapped in one place.
+// A nil cfg removes the alias.
+//
+//	client.SetLLMAlias("cheap", &scrapeapi.LLMConfig{Model: "openai/gpt-4o-mini"})
+//	client.SetLLMAlias("accurate", &scrapeapi.LLMConfig{Model: "openai/gpt-4o"})
+func (c *Client) SetLLMAlias(name string, cfg *LLMConfig) {
+	c.llmMu.Lock()
+	defer c.llmMu.Unlock()
+	if cfg == nil {
+		delete(c.llmAliases, name)
+		return
+	}
+	if c.llmAliases == nil {
+		c.llmAliases = map[string]*LLMConfig{}
+	}
+	c.llmAliases[name] = cfg.clone()
+}
+
+// resolveLLM returns req with the LLM configuration of its alias, or the
+// client default, filled in. req itself is not modified.
+func (c *Client) resolveLLM(req *ScrapeRequest) (*ScrapeRequest, error) {
+	if req.LLM != nil {
+		return req, nil
+	}
+
+	c.llmMu.RLock()
+	defer c.llmMu.RUnlock()
+
+	cfg := c.defaultLLM
+	if req.LLMAlias != "" {
+		var ok bool
+		if cfg, ok = c.llmAliases[req.LLMAlias]; !ok {
+			return nil, fmt.Errorf("unknown LLM alias %q", req.LLMAlias)
+		}
+	}
+	if cfg == nil {
+		return req, nil
+	}
+
+	resolved := *req
+	resolved.LLM = cfg.clone()
+	resolved.LLMAlias = ""
+	return &resolved, nil
+}
+
+func (l *LLMConfig) clone() *LLMConfig {
+	if l == nil {
+		return nil
+	}
+	cl := *l
+	cl.FallbackModels = append([]string(nil), l.FallbackModels...)
+	cl.StopSequences = append([]string(nil), l.StopSequences...)
+	if l.Bedrock != nil {
+		bedrock := *l.Bedrock
+		cl.Bedrock = &bedrock
+	}
+	return &cl
+}
+
+// BedrockCredentials selects how the server authenticates to AWS Bedrock
+type BedrockCredentials string
+
+// Credential sources for BedrockConfig
+const (
+	// BedrockDefaultCredentials uses the server's default AWS credential chain
+	BedrockDefaultCredentials BedrockCredentials = "default"
+	// BedrockProfileCredentials uses a named profile from the server's AWS config
+	BedrockProfileCredentials BedrockCredentials = "profile"
+	// BedrockStaticCredentials uses the access key sent with the request
+	BedrockStaticCredentials BedrockCredentials = "static"
+	// BedrockAssumeRole assumes RoleARN, usually in the customer's own account,
+	// starting from the server's default credentials
+	BedrockAssumeRole BedrockCredentials = "assume_role"
+)
+
+// BedrockConfig configures access to AWS Bedrock
+type BedrockConfig struct {
+	Region string `json:"region"`
+	// Credentials defaults to BedrockDefaultCredentials when empty
+	Credentials BedrockCredentials `json:"credentials,omitempty"`
+	// Profile is the AWS profile for BedrockProfileCredentials
+	Profile string `json:"profile,omitempty"`
+	// AccessKeyID, SecretAccessKey and SessionToken are the keys for BedrockStaticCredentials
+	AccessKeyID     string `json:"access_key_id,omitempty"`
+	SecretAccessKey string `json:"secret_access_key,omitempty"`
+	SessionToken    string `json:"session_token,omitempty"`
+	// RoleARN and ExternalID identify the role for BedrockAssumeRole
+	RoleARN    string `json:"role_arn,omitempty"`
+	ExternalID string `json:"external_id,omitempty"`
+}
+
+// BedrockLLM configures a Bedrock model in region using the server's default
+// AWS credentials. modelID is the Bedrock model ID, e.g.
+// "anthropic.claude-3-5-sonnet-20240620-v1:0"; set Bedrock.Credentials on the
+// result to authenticate differently.
+//
+//	llm := scrapeapi.BedrockLLM("us-east-1", "anthropic.claude-3-5-sonnet-20240620-v1:0")
+//	llm.Bedrock.Credentials = scrapeapi.BedrockAssumeRole
+//	llm.Bedrock.RoleARN = "arn:aws:iam::123456789012:role/scrapeapi-bedrock"
+func BedrockLLM(region, modelID string) *LLMConfig {
+	return &LLMConfig{
+		Model:    "bedrock/" + modelID,
+		Provider: "bedrock",
+		Bedrock:  &BedrockConfig{Region: region},
+	}
+}
+
+func (b *BedrockConfig) validate(v *validator, field string) {
+	if b.Region == "" {
+		v.addf(field+".region", "is required")
+	}
+
+	static := b.AccessKeyID != "" || b.SecretAccessKey != "" || b.SessionToken != ""
+	switch b.Credentials {
+	case "", BedrockDefaultCredentials:
+	case BedrockProfileCredentials:
+		if b.Profile == "" {
+			v.addf(field+".profile", "is required for %s credentials", b.Credentials)
+		}
+	case BedrockStaticCredentials:
+		if b.AccessKeyID == "" {
+			v.addf(field+".access_key_id", "is required for %s credentials", b.Credentials)
+		}
+		if b.SecretAccessKey == "" {
+			v.addf(field+".secret_access_key", "is required for %s credentials", b.Credentials)
+		}
+	case BedrockAssumeRole:
+		if b.RoleARN == "" {
+			v.addf(field+".role_arn", "is required for %s credentials", b.Credentials)
+		} else if !strings.HasPrefix(b.RoleARN, "arn:") {
+			v.addf(field+".role_arn", "must be an ARN, got %q", b.RoleARN)
+		}
+	default:
+		v.addf(field+".credentials", "unknown credential source %q", b.Credentials)
+	}
+
+	if static && b.Credentials != BedrockStaticCredentials {
+		v.addf(field+".access_key_id", "is only used with %s credentials", BedrockStaticCredentials)
+	}
+	if b.ExternalID != "" && b.Credentials != BedrockAssumeRole {
+		v.addf(field+".external_id", "is only used with %s credentials", BedrockAssumeRole)
+	}
+}
+
+func (l *LLMConfig) validate(v *validator, field string) {
+	if l.Temperature != nil && *l.Temperature < 0 {
+		v.addf(field+".temperature", "must not be negative, got %g", *l.Temperature)
+	}
+	if l.TopP != nil && (*l.TopP <= 0 || *l.TopP > 1) {
+		v.addf(field+".top_p", "must be in (0, 1], got %g", *l.TopP)
+	}
+	if l.MaxTokens != nil && *l.MaxTokens <= 0 {
+		v.addf(field+".max_tokens", "must be positive, got %d", *l.MaxTokens)
+	}
+	if l.FrequencyPenalty != nil && (*l.FrequencyPenalty < -2 || *l.FrequencyPenalty > 2) {
+		v.addf(field+".frequency_penalty", "must be in [-2, 2], got %g", *l.FrequencyPenalty)
+	}
+	for i, stop := range l.StopSequences {
+		if stop == "" {
+			v.addf(fmt.Sprintf("%s.stop[%d]", field, i), "must not be empty")
+		}
+	}
+	for i, m := range l.FallbackModels {
+		switch {
+		case m == "":
+			v.addf(fmt.Sprintf("%s.fallback_models[%d]", field, i), "must not be empty")
+		case m == l.Model:
+			v.addf(fmt.Sprintf("%s.fallback_models[%d]", field, i), "repeats the primary model %q", m)
+		}
+	}
+
+	if l.AzureEndpoint != "" || l.AzureDeployment != "" || l.APIVersion != "" {
+		if l.AzureEndpoint == "" {
+			v.addf(field+".azure_endpoint", "is required for Azure OpenAI")
+		} else {
+			v.checkURL(field+".azure_endpoint", l.AzureEndpoint)
+		}
+		if l.AzureDeployment == "" {
+			v.addf(field+".azure_deployment", "is required for Azure OpenAI")
+		}
+		if l.APIVersion == "" {
+			v.addf(field+".api_version", "is required for Azure OpenAI")
+		}
+	}
+	if l.Bedrock != nil {
+		l.Bedrock.validate(v, field+".bedrock")
+	}
+}
@@ -0,0 +1,101 @@
+package scrapeapi
+
+import (
+	"context"
+	"iter"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+// followPollInterval is how often FollowScrapeLogs asks for new log entries
+const followPollInterval = time.Second
+
+// LogLevel is the severity of a job log entry
+type LogLevel string
+
+// Log levels reported by the API
+const (
+	LogLevelDebug LogLevel = "debug"
+	LogLevelInfo  LogLevel = "info"
+	LogLevelWarn  LogLevel = "warning"
+	LogLevelError LogLevel = "error"
+)
+
+// LogOptions filters GetScrapeLogs results. Zero values are not sent to the server.
+type LogOptions struct {
+	// Tail returns only the last Tail entries
+	Tail int
+	// After returns only entries with a greater Sequence
+	After int64
+	// MinLevel drops entries below this level
+	MinLevel LogLevel
+}
+
+func (o LogOptions) query() url.Values {
+	q := url.Values{}
+	if o.Tail > 0 {
+		q.Set("tail", strconv.Itoa(o.Tail))
+	}
+	if o.After > 0 {
+		q.Set("after", strconv.FormatInt(o.After, 10))
+	}
+	if o.MinLevel != "" {
+		q.Set("level", string(o.MinLevel))
+	}
+	return q
+}
+
+// GetScrapeLogs returns the server-side log of a job
+func (c *Client) GetScrapeLogs(ctx context.Context, requestID string, opts LogOptions) (*ScrapeLogs, error) {
+	// Create a span for this operation
+	// If there's no existing span in context, this creates a new root span
+	ctx, span := c.tracer.Start(ctx, "scrapeapi.GetScrapeLogs")
+	defer span.End()
+
+	path := "/v1/scrape/" + url.PathEscape(requestID) + "/logs"
+	if q := opts.query(); len(q) > 0 {
+		path += "?" + q.Encode()
+	}
+
+	var logs ScrapeLogs
+	if err := c.do(ctx, http.MethodGet, path, nil, &logs); err != nil {
+		return nil, err
+	}
+
+	return &logs, nil
+}
+
+// FollowScrapeLogs yields the log entries of a job as they are written, like tail -f.
+// It ends once the job has finished and its remaining entries are delivered.
+// Iteration stops at the first error, which is yielded with a zero entry.
+func (c *Client) FollowScrapeLogs(ctx context.Context, requestID string, opts LogOptions) iter.Seq2[LogEntry, error] {
+	return func(yield func(LogEntry, error) bool) {
+		for {
+			logs, err := c.GetScrapeLogs(ctx, requestID, opts)
+			if err != nil {
+				yield(LogEntry{}, err)
+				return
+			}
+
+			for _, entry := range logs.Entries {
+				if !yield(entry, nil) {
+					return
+				}
+				opts.After = entry.Sequence
+			}
+			// Tail only applies to the first read
+			opts.Tail = 0
+
+			if logs.Status.IsTerminal() {
+				return
+			}
+
+			if err := sleep(ctx, followPollInterval); err != nil {
+				yield(LogEntry{}, err)
+				return
+			}
+		}
+	}
+}
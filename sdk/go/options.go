@@ -0,0 +1,129 @@
+package scrapeapi
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// ClientOption is a functional option for configuring a Client
+type ClientOption func(*Client)
+
+// WithLogger sets the logger used for SDK diagnostics.
+// By default the client is silent; span and trace details are logged at debug level.
+func WithLogger(logger *slog.Logger) ClientOption {
+	return func(c *Client) {
+		if logger == nil {
+			logger = slog.New(discardHandler{})
+		}
+		c.logger = logger
+	}
+}
+
+// WithTracerProvider sets the tracer provider used for client spans and
+// HTTP transport instrumentation instead of the global one
+func WithTracerProvider(tp trace.TracerProvider) ClientOption {
+	return func(c *Client) {
+		c.tracerProvider = tp
+	}
+}
+
+// WithPropagator sets how the context of a call is propagated to the API, in
+// the request headers and in the payload of submitted jobs. By default the
+// W3C trace context is sent, along with whatever the global propagator set
+// with otel.SetTextMapPropagator propagates. To send W3C baggage, such as
+// tenant or feature metadata, regardless of the global setting:
+//
+//	scrapeapi.WithPropagator(propagation.NewCompositeTextMapPropagator(
+//		propagation.TraceContext{}, propagation.Baggage{}))
+//
+// A nil propagator uses only the global one.
+func WithPropagator(p propagation.TextMapPropagator) ClientOption {
+	return func(c *Client) {
+		if p == nil {
+			p = otel.GetTextMapPropagator()
+		}
+		c.propagator = p
+	}
+}
+
+// WithTracingDisabled turns off span creation and HTTP transport instrumentation
+func WithTracingDisabled() ClientOption {
+	return func(c *Client) {
+		c.tracingDisabled = true
+	}
+}
+
+// WithDefaultHeader adds a header sent with every API call, e.g. an organization ID.
+// It can be given several times; per-request headers override it.
+func WithDefaultHeader(key, value string) ClientOption {
+	return func(c *Client) {
+		if c.defaultHeaders == nil {
+			c.defaultHeaders = http.Header{}
+		}
+		c.defaultHeaders.Add(key, value)
+	}
+}
+
+// RequestOption is a functional option for configuring a single API call
+type RequestOption func(*requestConfig)
+
+type requestConfig struct {
+	header http.Header
+	// idempotent marks a POST that is safe to send more than once
+	idempotent bool
+}
+
+// newRequestConfig applies opts to a fresh requestConfig
+func newRequestConfig(opts []RequestOption) *requestConfig {
+	cfg := &requestConfig{}
+	for _, opt := range opts {
+		opt(cfg)
+	}
+	return cfg
+}
+
+// idempotentCall marks a POST whose repetition has no further effect, so
+// it may be retried without an Idempotency-Key
+func idempotentCall() RequestOption {
+	return func(cfg *requestConfig) {
+		cfg.idempotent = true
+	}
+}
+
+// WithHeader adds a header to a single API call, overriding a default header with the same key
+func WithHeader(key, value string) RequestOption {
+	return func(cfg *requestConfig) {
+		if cfg.header == nil {
+			cfg.header = http.Header{}
+		}
+		cfg.header.Add(key, value)
+	}
+}
+
+// logSpanContext logs the trace details of span at debug level
+func (c *Client) logSpanContext(ctx context.Context, msg string, span trace.Span) {
+	sc := span.SpanContext()
+	if !sc.IsValid() {
+		c.logger.DebugContext(ctx, msg, "span_valid", false)
+		return
+	}
+	c.logger.DebugContext(ctx, msg,
+		"span_valid", true,
+		"trace_id", sc.TraceID().String(),
+		"span_id", sc.SpanID().String(),
+		"sampled", sc.IsSampled(),
+	)
+}
+
+// discardHandler is a slog.Handler that drops every record
+type discardHandler struct{}
+
+func (discardHandler) Enabled(context.Context, slog.Level) bool  { return false }
+func (discardHandler) Handle(context.Context, slog.Record) error { return nil }
+func (h discardHandler) WithAttrs([]slog.Attr) slog.Handler      { return h }
+func (h discardHandler) WithGroup(string) slog.Handler           { return h }
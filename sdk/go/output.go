@@ -0,0 +1,111 @@
+package scrapeapi
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"iter"
+	"strings"
+)
+
+// OutputFormat selects what a job returns: structured data extracted by the
+// LLM as JSON, NDJSON or CSV, or the page content itself as markdown, plain text or tables
+type OutputFormat string
+
+// Output formats supported by the API
+const (
+	// OutputJSON extracts structured data into Result; this is the default
+	OutputJSON OutputFormat = "json"
+	// OutputMarkdown returns the cleaned page as markdown in Content
+	OutputMarkdown OutputFormat = "markdown"
+	// OutputText returns the cleaned page as plain text in Content
+	OutputText OutputFormat = "text"
+	// OutputTables returns the HTML tables of the page verbatim in Tables
+	OutputTables OutputFormat = "tables"
+	// OutputNDJSON returns extracted list items one JSON object per line in Content; see NDJSON
+	OutputNDJSON OutputFormat = "ndjson"
+	// OutputCSV returns extracted list items as CSV with a header row in Content; see CSV
+	OutputCSV OutputFormat = "csv"
+)
+
+// IsValid reports whether f is one of the known output formats
+func (f OutputFormat) IsValid() bool {
+	switch f {
+	case OutputJSON, OutputMarkdown, OutputText, OutputTables, OutputNDJSON, OutputCSV:
+		return true
+	}
+	return false
+}
+
+// isContent reports whether f returns page content rather than data extracted by the LLM
+func (f OutputFormat) isContent() bool {
+	return f == OutputMarkdown || f == OutputText || f == OutputTables
+}
+
+// isJSON reports whether f puts extracted data into Result
+func (f OutputFormat) isJSON() bool {
+	return f == "" || f == OutputJSON
+}
+
+func (f OutputFormat) String() string {
+	return string(f)
+}
+
+// NDJSON iterates over the items of an OutputNDJSON job, one raw JSON value
+// per line of Content. Iteration stops at the first malformed line, which is
+// yielded as an error with its line number.
+//
+//	for item, err := range resp.NDJSON() {
+//		if err != nil {
+//			return err
+//		}
+//		var job Job
+//		if err := json.Unmarshal(item, &job); err != nil {
+//			return err
+//		}
+//	}
+func (r *ScrapeResponse) NDJSON() iter.Seq2[json.RawMessage, error] {
+	return func(yield func(json.RawMessage, error) bool) {
+		if r.Content == "" {
+			yield(nil, ErrNoResult)
+			return
+		}
+
+		scanner := bufio.NewScanner(strings.NewReader(r.Content))
+		scanner.Buffer(make([]byte, 64*1024), 16<<20)
+		for n := 1; scanner.Scan(); n++ {
+			line := bytes.TrimSpace(scanner.Bytes())
+			if len(line) == 0 {
+				continue
+			}
+			if !json.Valid(line) {
+				yield(nil, fmt.Errorf("decode ndjson: line %d is not valid JSON", n))
+				return
+			}
+			if !yield(json.RawMessage(bytes.Clone(line)), nil) {
+				return
+			}
+		}
+		if err := scanner.Err(); err != nil {
+			yield(nil, fmt.Errorf("decode ndjson: %w", err))
+		}
+	}
+}
+
+// CSV parses the Content of an OutputCSV job into its header row and data rows
+func (r *ScrapeResponse) CSV() (header []string, rows [][]string, err error) {
+	if r.Content == "" {
+		return nil, nil, ErrNoResult
+	}
+
+	records, err := csv.NewReader(strings.NewReader(r.Content)).ReadAll()
+	if err != nil {
+		return nil, nil, fmt.Errorf("decode csv: %w", err)
+	}
+	if len(records) == 0 {
+		return nil, nil, ErrNoResult
+	}
+	return records[0], records[1:], nil
+}
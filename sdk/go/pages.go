@@ -0,0 +1,7 @@
+package scrapeapi
+
+// DecodeResult decodes the page result into the value pointed to by into,
+// as strictly as ScrapeResponse.DecodeResult
+func (p *PageResult) DecodeResult(into interface{}) error {
+	return decodeResult(resultData(p.Result), into)
+}
@@ -0,0 +1,274 @@
+// Package pipeline runs ScrapeRequests from a source through transform
+// stages into sinks.
+//
+// Requests are scraped concurrently with a scrapeapi.Pool, then each
+// result passes through the stages in order, e.g. decoding, validation and
+// normalization, and is written to every sink. Items that fail anywhere are
+// routed to the error sink. Backpressure is end to end: when the sinks fall
+// behind, fewer requests are read from the source.
+//
+//	p := pipeline.New(client, pipeline.FromURLs(req, urls...),
+//		pipeline.WithStages(
+//			pipeline.Decode[JobPage](),
+//			pipeline.Validate(func(page JobPage) error { ... }),
+//			pipeline.Map(normalizeJobs),
+//		),
+//		pipeline.WithSinks(sink),
+//		pipeline.WithErrorSink(pipeline.SinkFunc(func(ctx context.Context, item *pipeline.Item) error {
+//			log.Printf("%s failed at %s: %v", *item.Request.WebsiteURL, item.Stage, item.Err)
+//			return nil
+//		})),
+//		pipeline.WithPoolOptions(scrapeapi.WithConcurrency(10), scrapeapi.WithJobRetries(2, time.Second)),
+//	)
+//	stats, err := p.Run(ctx)
+package pipeline
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"iter"
+	"sync/atomic"
+
+	scrapeapi "github.com/dir01/scrapeapi/sdk/go"
+)
+
+// defaultMaxInFlight is how many requests may be on their way through a
+// pipeline unless WithMaxInFlight is given
+const defaultMaxInFlight = 16
+
+// Item is one request on its way through a pipeline
+type Item struct {
+	Request  *scrapeapi.ScrapeRequest
+	Response *scrapeapi.ScrapeResponse
+	// Attempts is how many jobs were started for Request
+	Attempts int
+	// Value is what the stages have made of the result so far; it starts
+	// out as the result data, without the envelope the API may wrap it in
+	Value interface{}
+	// Err is why the item failed; set on items written to the error sink
+	Err error
+	// Stage is where the item failed: "scrape", the name of a stage or "sink"
+	Stage string
+}
+
+// NewItem returns the item for a Pool result, for writing the results of
+// a Pool to sinks without running a pipeline
+func NewItem(result scrapeapi.PoolResult) *Item {
+	item := &Item{Request: result.Request, Response: result.Response, Attempts: result.Attempts, Err: result.Err}
+	if result.Response != nil {
+		item.Value = result.Response.Result
+		var data interface{}
+		if err := result.Response.DecodeResult(&data); err == nil {
+			item.Value = data
+		}
+	}
+	if result.Err != nil {
+		item.Stage = "scrape"
+	}
+	return item
+}
+
+// Source yields the requests to scrape. An error ends the pipeline.
+type Source iter.Seq2[*scrapeapi.ScrapeRequest, error]
+
+// Sink receives the items that made it through every stage. Sinks are
+// written from one goroutine, so they need not be safe for concurrent use.
+type Sink interface {
+	Write(ctx context.Context, item *Item) error
+	// Close flushes anything buffered; it is called once the pipeline is done
+	Close(ctx context.Context) error
+}
+
+// SinkFunc adapts a function to a Sink with nothing to close
+type SinkFunc func(ctx context.Context, item *Item) error
+
+func (f SinkFunc) Write(ctx context.Context, item *Item) error {
+	return f(ctx, item)
+}
+
+func (f SinkFunc) Close(ctx context.Context) error {
+	return nil
+}
+
+// Stats counts what happened to the requests of a run
+type Stats struct {
+	// Submitted is how many requests were read from the source
+	Submitted int
+	// Succeeded is how many items were written to every sink
+	Succeeded int
+	// Failed is how many items failed to scrape or in a stage or sink
+	Failed int
+	// Skipped is how many items a stage dropped with ErrSkip
+	Skipped int
+}
+
+// Option is a functional option for configuring a Pipeline
+type Option func(*Pipeline)
+
+// WithStages appends stages, which run in the order given
+func WithStages(stages ...Stage) Option {
+	return func(p *Pipeline) {
+		p.stages = append(p.stages, stages...)
+	}
+}
+
+// WithSinks appends sinks. Every item is written to each of them in order;
+// if one fails, the item goes to the error sink and the rest are skipped.
+func WithSinks(sinks ...Sink) Option {
+	return func(p *Pipeline) {
+		p.sinks = append(p.sinks, sinks...)
+	}
+}
+
+// WithErrorSink routes failed items to sink, with Err and Stage set, and
+// keeps the pipeline going. Without one, the first failure ends the run.
+func WithErrorSink(sink Sink) Option {
+	return func(p *Pipeline) {
+		p.errorSink = sink
+	}
+}
+
+// WithPoolOptions configures the pool the requests are scraped with, e.g.
+// its concurrency and retries
+func WithPoolOptions(opts ...scrapeapi.PoolOption) Option {
+	return func(p *Pipeline) {
+		p.poolOpts = append(p.poolOpts, opts...)
+	}
+}
+
+// WithMaxInFlight caps how many requests may be read from the source but
+// not yet through the sinks (16 by default). It should be at least the
+// pool's concurrency, or slots go unused.
+func WithMaxInFlight(n int) Option {
+	return func(p *Pipeline) {
+		if n > 0 {
+			p.maxInFlight = n
+		}
+	}
+}
+
+// Pipeline scrapes the requests of a source into sinks. A Pipeline can be
+// run more than once, but its sinks are closed at the end of every run.
+type Pipeline struct {
+	client      scrapeapi.ScrapeClient
+	source      Source
+	stages      []Stage
+	sinks       []Sink
+	errorSink   Sink
+	poolOpts    []scrapeapi.PoolOption
+	maxInFlight int
+}
+
+// New returns a pipeline scraping the requests of source with client
+func New(client scrapeapi.ScrapeClient, source Source, opts ...Option) *Pipeline {
+	p := &Pipeline{client: client, source: source, maxInFlight: defaultMaxInFlight}
+	for _, opt := range opts {
+		opt(p)
+	}
+	return p
+}
+
+// Run scrapes every request of the source and returns once all of them are
+// through the pipeline and the sinks are closed. The error is set if the
+// source failed, an item failed without an error sink, the error sink
+// failed, a sink could not be closed or ctx ended; items still in flight
+// when the run stops early are dropped.
+func (p *Pipeline) Run(ctx context.Context) (*Stats, error) {
+	runCtx, cancel := context.WithCancelCause(ctx)
+	defer cancel(nil)
+
+	pool := scrapeapi.NewPool(runCtx, p.client, p.poolOpts...)
+	inFlight := make(chan struct{}, p.maxInFlight)
+	var submitted atomic.Int64
+	go func() {
+		defer pool.Close()
+		for req, err := range p.source {
+			if err != nil {
+				cancel(fmt.Errorf("source: %w", err))
+				return
+			}
+			// Wait for room, so a slow sink slows down reading the source
+			select {
+			case inFlight <- struct{}{}:
+			case <-runCtx.Done():
+				return
+			}
+			if err := pool.Submit(req); err != nil {
+				return
+			}
+			submitted.Add(1)
+		}
+	}()
+	// A source blocked waiting for requests must not keep a stopped run going
+	go func() {
+		<-runCtx.Done()
+		pool.Close()
+	}()
+
+	stats := &Stats{}
+	for result := range pool.Results() {
+		<-inFlight
+		if runCtx.Err() != nil {
+			// The run has stopped; drain the jobs canceled with it
+			continue
+		}
+		if err := p.process(runCtx, NewItem(result), stats); err != nil {
+			cancel(err)
+		}
+	}
+	stats.Submitted = int(submitted.Load())
+
+	// Close the sinks even after a failure, so they flush what they got
+	closeCtx := context.WithoutCancel(ctx)
+	var closeErrs []error
+	for _, sink := range append(p.sinks, p.errorSink) {
+		if sink == nil {
+			continue
+		}
+		if err := sink.Close(closeCtx); err != nil {
+			closeErrs = append(closeErrs, fmt.Errorf("close sink: %w", err))
+		}
+	}
+
+	// The cause is whatever stopped the run early, if anything did
+	return stats, errors.Join(append([]error{context.Cause(runCtx)}, closeErrs...)...)
+}
+
+// process runs one scraped item through the stages and sinks. It returns
+// an error only when the run has to stop.
+func (p *Pipeline) process(ctx context.Context, item *Item, stats *Stats) error {
+	if item.Err != nil {
+		return p.fail(ctx, item, "scrape", item.Err, stats)
+	}
+
+	for _, stage := range p.stages {
+		if err := stage.Apply(ctx, item); err != nil {
+			if errors.Is(err, ErrSkip) {
+				stats.Skipped++
+				return nil
+			}
+			return p.fail(ctx, item, stage.Name, err, stats)
+		}
+	}
+	for _, sink := range p.sinks {
+		if err := sink.Write(ctx, item); err != nil {
+			return p.fail(ctx, item, "sink", err, stats)
+		}
+	}
+	stats.Succeeded++
+	return nil
+}
+
+// fail routes a failed item to the error sink, or stops the run without one
+func (p *Pipeline) fail(ctx context.Context, item *Item, stage string, err error, stats *Stats) error {
+	stats.Failed++
+	item.Stage, item.Err = stage, err
+	if p.errorSink == nil {
+		return fmt.Errorf("%s: %w", stage, err)
+	}
+	if err := p.errorSink.Write(ctx, item); err != nil {
+		return fmt.Errorf("error sink: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,152 @@
+package pipeline
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	scrapeapi "github.com/dir01/scrapeapi/sdk/go"
+	"github.com/dir01/scrapeapi/sdk/go/scrapeapitest"
+)
+
+// recordingSink remembers the URLs written to it, and the stage each failed at
+type recordingSink struct {
+	urls   []string
+	stages map[string]string
+	failOn string
+	closed bool
+}
+
+func (s *recordingSink) Write(ctx context.Context, item *Item) error {
+	u := *item.Request.WebsiteURL
+	if u == s.failOn {
+		return errors.New("disk full")
+	}
+	s.urls = append(s.urls, u)
+	if item.Err != nil {
+		if s.stages == nil {
+			s.stages = map[string]string{}
+		}
+		s.stages[u] = item.Stage
+	}
+	return nil
+}
+
+func (s *recordingSink) Close(ctx context.Context) error {
+	s.closed = true
+	return nil
+}
+
+// newTestServer returns a server whose jobs return their URL, except for
+// URLs containing "fail", whose jobs fail
+func newTestServer(t *testing.T) *scrapeapitest.Server {
+	t.Helper()
+	srv := scrapeapitest.NewServer(scrapeapitest.WithLifecycleFunc(func(req *scrapeapi.ScrapeRequest) scrapeapitest.Lifecycle {
+		if strings.Contains(*req.WebsiteURL, "fail") {
+			return scrapeapitest.Lifecycle{Error: "page not found"}
+		}
+		return scrapeapitest.Lifecycle{Result: map[string]interface{}{"url": *req.WebsiteURL}}
+	}))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+var testRequest = &scrapeapi.ScrapeRequest{Graph: scrapeapi.GraphSmart, UserPrompt: "Extract the page title"}
+
+// fastPolling keeps the pool from waiting the default poll interval
+var fastPolling = WithPoolOptions(scrapeapi.WithPoolWaitOptions(scrapeapi.WithPollInterval(time.Millisecond)))
+
+// checkURL skips and rejects items by their URL
+var checkURL = StageFunc("check", func(ctx context.Context, item *Item) error {
+	u := item.Value.(map[string]interface{})["url"].(string)
+	switch {
+	case strings.HasSuffix(u, "/skip"):
+		return ErrSkip
+	case strings.HasSuffix(u, "/invalid"):
+		return errors.New("no title")
+	}
+	return nil
+})
+
+func TestRunWithErrorSink(t *testing.T) {
+	srv := newTestServer(t)
+	sink := &recordingSink{failOn: "https://example.com/full"}
+	errSink := &recordingSink{}
+	p := New(srv.SDKClient(), FromURLs(testRequest,
+		"https://example.com/ok", "https://example.com/fail", "https://example.com/skip",
+		"https://example.com/invalid", "https://example.com/full"),
+		WithStages(checkURL), WithSinks(sink), WithErrorSink(errSink), fastPolling)
+
+	stats, err := p.Run(context.Background())
+	if err != nil {
+		t.Fatalf("Run: %v", err)
+	}
+	if want := (Stats{Submitted: 5, Succeeded: 1, Failed: 3, Skipped: 1}); *stats != want {
+		t.Errorf("stats = %+v, want %+v", *stats, want)
+	}
+	if want := []string{"https://example.com/ok"}; !reflect.DeepEqual(sink.urls, want) {
+		t.Errorf("sink got %v, want %v", sink.urls, want)
+	}
+	want := map[string]string{
+		"https://example.com/fail":    "scrape",
+		"https://example.com/invalid": "check",
+		"https://example.com/full":    "sink",
+	}
+	if !reflect.DeepEqual(errSink.stages, want) {
+		t.Errorf("error sink got %v, want %v", errSink.stages, want)
+	}
+	if !sink.closed || !errSink.closed {
+		t.Error("sinks were not closed")
+	}
+}
+
+func TestRunStopsOnFirstFailure(t *testing.T) {
+	srv := newTestServer(t)
+	sink := &recordingSink{}
+	p := New(srv.SDKClient(), FromURLs(testRequest,
+		"https://example.com/fail", "https://example.com/a", "https://example.com/b"),
+		WithSinks(sink), WithMaxInFlight(1), WithPoolOptions(scrapeapi.WithConcurrency(1)), fastPolling)
+
+	stats, err := p.Run(context.Background())
+	var jobErr *scrapeapi.JobError
+	if !errors.As(err, &jobErr) || !strings.HasPrefix(err.Error(), "scrape: ") {
+		t.Fatalf("Run error = %v, want the job error of the scrape stage", err)
+	}
+	if stats.Failed != 1 || stats.Succeeded != 0 {
+		t.Errorf("stats = %+v, want only the failure", *stats)
+	}
+	if len(sink.urls) != 0 {
+		t.Errorf("sink got %v after the run stopped", sink.urls)
+	}
+	if !sink.closed {
+		t.Error("sink was not closed after the run stopped")
+	}
+}
+
+func TestRunSourceError(t *testing.T) {
+	srv := newTestServer(t)
+	errBroken := errors.New("connection reset")
+	source := Source(func(yield func(*scrapeapi.ScrapeRequest, error) bool) {
+		req := *testRequest
+		req.WebsiteURL = scrapeapi.String("https://example.com/ok")
+		if yield(&req, nil) {
+			yield(nil, errBroken)
+		}
+	})
+	sink, errSink := &recordingSink{}, &recordingSink{}
+	p := New(srv.SDKClient(), source, WithSinks(sink), WithErrorSink(errSink), fastPolling)
+
+	_, err := p.Run(context.Background())
+	if !errors.Is(err, errBroken) || !strings.HasPrefix(err.Error(), "source: ") {
+		t.Fatalf("Run error = %v, want the source error", err)
+	}
+	if len(errSink.urls) != 0 {
+		t.Errorf("error sink got %v, want the source error to end the run instead", errSink.urls)
+	}
+	if !sink.closed || !errSink.closed {
+		t.Error("sinks were not closed after the source failed")
+	}
+}
@@ -0,0 +1,289 @@
+package pipeline
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"iter"
+	"reflect"
+	"slices"
+	"strings"
+)
+
+// maxPostgresParams is how many parameters Postgres takes in one statement
+const maxPostgresParams = 65535
+
+// Column is one column of the rows a PostgresSink upserts
+type Column struct {
+	Name string
+	// Value returns the column's value for a row of item: the item's value,
+	// or one element of it with PostgresConfig.RowPerElement
+	Value func(item *Item, row interface{}) (interface{}, error)
+}
+
+// RequestIDColumn holds the job's request ID
+func RequestIDColumn(name string) Column {
+	return Column{Name: name, Value: func(item *Item, row interface{}) (interface{}, error) {
+		return NewRecord(item).RequestID, nil
+	}}
+}
+
+// WebsiteURLColumn holds the scraped URL, or NULL if there is none
+func WebsiteURLColumn(name string) Column {
+	return Column{Name: name, Value: func(item *Item, row interface{}) (interface{}, error) {
+		if u := NewRecord(item).WebsiteURL; u != "" {
+			return u, nil
+		}
+		return nil, nil
+	}}
+}
+
+// CreatedAtColumn holds when the job was created, or NULL if unknown
+func CreatedAtColumn(name string) Column {
+	return Column{Name: name, Value: func(item *Item, row interface{}) (interface{}, error) {
+		if t := NewRecord(item).CreatedAt; t != nil {
+			return *t, nil
+		}
+		return nil, nil
+	}}
+}
+
+// JSONColumn holds the whole row as JSON, for a jsonb column
+func JSONColumn(name string) Column {
+	return Column{Name: name, Value: func(item *Item, row interface{}) (interface{}, error) {
+		data, err := json.Marshal(row)
+		if err != nil {
+			return nil, err
+		}
+		return string(data), nil
+	}}
+}
+
+// FieldColumn holds one field of the row by its JSON name, or NULL if the
+// row has no such field. Objects and arrays are stored as JSON.
+func FieldColumn(name, field string) Column {
+	return Column{Name: name, Value: func(item *Item, row interface{}) (interface{}, error) {
+		fields, ok := row.(map[string]interface{})
+		if !ok {
+			// Structs and typed maps, through their JSON form
+			data, err := json.Marshal(row)
+			if err != nil {
+				return nil, err
+			}
+			if err := json.Unmarshal(data, &fields); err != nil {
+				return nil, fmt.Errorf("column %s: row is not an object", name)
+			}
+		}
+		switch v := fields[field].(type) {
+		case map[string]interface{}, []interface{}:
+			data, err := json.Marshal(v)
+			if err != nil {
+				return nil, err
+			}
+			return string(data), nil
+		default:
+			return v, nil
+		}
+	}}
+}
+
+// RecordColumns are the columns a PostgresSink writes by default: the
+// Record of every item, with its result as jsonb.
+//
+//	CREATE TABLE scrape_results (
+//		request_id  text PRIMARY KEY,
+//		website_url text,
+//		created_at  timestamptz,
+//		result      jsonb
+//	);
+func RecordColumns() []Column {
+	return []Column{
+		RequestIDColumn("request_id"),
+		WebsiteURLColumn("website_url"),
+		CreatedAtColumn("created_at"),
+		JSONColumn("result"),
+	}
+}
+
+// PostgresConfig configures a PostgresSink
+type PostgresConfig struct {
+	// Table is the table to upsert into, optionally schema-qualified
+	Table string
+	// Columns are the columns to write; RecordColumns if empty
+	Columns []Column
+	// ConflictKey names the columns of the unique constraint rows are
+	// upserted on, request_id if empty. Rows that share a key within one
+	// item are upserted once, with the last one winning.
+	ConflictKey []string
+	// RowPerElement writes one row per element of a slice value, such as
+	// the listings decoded from one page, instead of one row per item
+	RowPerElement bool
+}
+
+// PostgresSink upserts items into a Postgres table through database/sql.
+// The SDK imports no driver; open db with one such as pgx's stdlib or lib/pq.
+type PostgresSink struct {
+	db      *sql.DB
+	columns []Column
+	key     []int
+	perElem bool
+	insert  string
+	suffix  string
+}
+
+// NewPostgresSink returns a sink upserting into cfg.Table. Every item is
+// written in one transaction, so its rows land together or not at all.
+func NewPostgresSink(db *sql.DB, cfg PostgresConfig) (*PostgresSink, error) {
+	if cfg.Table == "" {
+		return nil, errors.New("postgres sink: table is required")
+	}
+	columns := cfg.Columns
+	if len(columns) == 0 {
+		columns = RecordColumns()
+	}
+	keyNames := cfg.ConflictKey
+	if len(keyNames) == 0 {
+		keyNames = []string{"request_id"}
+	}
+
+	s := &PostgresSink{db: db, columns: columns, perElem: cfg.RowPerElement}
+	names := make([]string, len(columns))
+	for i, c := range columns {
+		if c.Name == "" || c.Value == nil {
+			return nil, fmt.Errorf("postgres sink: column %d needs a name and a value", i)
+		}
+		names[i] = c.Name
+	}
+	var quotedKey, updates []string
+	for _, k := range keyNames {
+		i := slices.Index(names, k)
+		if i < 0 {
+			return nil, fmt.Errorf("postgres sink: conflict key %s is not one of the columns", k)
+		}
+		s.key = append(s.key, i)
+		quotedKey = append(quotedKey, quoteIdent(k))
+	}
+	quoted := make([]string, len(names))
+	for i, name := range names {
+		quoted[i] = quoteIdent(name)
+		if !slices.Contains(keyNames, name) {
+			updates = append(updates, fmt.Sprintf("%s = EXCLUDED.%s", quoted[i], quoted[i]))
+		}
+	}
+
+	table := strings.Split(cfg.Table, ".")
+	for i, part := range table {
+		table[i] = quoteIdent(part)
+	}
+	s.insert = fmt.Sprintf("INSERT INTO %s (%s) VALUES ", strings.Join(table, "."), strings.Join(quoted, ", "))
+	s.suffix = fmt.Sprintf(" ON CONFLICT (%s) DO NOTHING", strings.Join(quotedKey, ", "))
+	if len(updates) > 0 {
+		s.suffix = fmt.Sprintf(" ON CONFLICT (%s) DO UPDATE SET %s", strings.Join(quotedKey, ", "), strings.Join(updates, ", "))
+	}
+	return s, nil
+}
+
+func (s *PostgresSink) Write(ctx context.Context, item *Item) error {
+	rows, err := s.rows(item)
+	if err != nil || len(rows) == 0 {
+		return err
+	}
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+	for query, args := range s.statements(rows) {
+		if _, err := tx.ExecContext(ctx, query, args...); err != nil {
+			return fmt.Errorf("upsert into postgres: %w", err)
+		}
+	}
+	return tx.Commit()
+}
+
+func (s *PostgresSink) Close(ctx context.Context) error {
+	return nil
+}
+
+// rows returns the column values of every row of item, one row per key
+func (s *PostgresSink) rows(item *Item) ([][]interface{}, error) {
+	values := []interface{}{item.Value}
+	if s.perElem {
+		values = nil
+		v := reflect.ValueOf(item.Value)
+		if v.Kind() != reflect.Slice && v.Kind() != reflect.Array {
+			return nil, fmt.Errorf("postgres sink: value is %T, want a slice for a row per element", item.Value)
+		}
+		for i := 0; i < v.Len(); i++ {
+			values = append(values, v.Index(i).Interface())
+		}
+	}
+
+	var rows [][]interface{}
+	seen := map[string]int{}
+	for _, value := range values {
+		row := make([]interface{}, len(s.columns))
+		for i, c := range s.columns {
+			v, err := c.Value(item, value)
+			if err != nil {
+				return nil, fmt.Errorf("column %s: %w", c.Name, err)
+			}
+			row[i] = v
+		}
+		// Postgres refuses to upsert the same key twice in one statement
+		var key strings.Builder
+		for _, i := range s.key {
+			fmt.Fprintf(&key, "%T:%v\x00", row[i], row[i])
+		}
+		if i, ok := seen[key.String()]; ok {
+			rows[i] = row
+			continue
+		}
+		seen[key.String()] = len(rows)
+		rows = append(rows, row)
+	}
+	return rows, nil
+}
+
+// statements splits the upsert of rows into statements within the
+// parameter limit of Postgres, yielding each with its arguments
+func (s *PostgresSink) statements(rows [][]interface{}) iter.Seq2[string, []interface{}] {
+	return func(yield func(string, []interface{}) bool) {
+		for chunk := range slices.Chunk(rows, maxPostgresParams/len(s.columns)) {
+			if !yield(s.statement(chunk)) {
+				return
+			}
+		}
+	}
+}
+
+// statement returns the upsert of rows with its arguments
+func (s *PostgresSink) statement(rows [][]interface{}) (string, []interface{}) {
+	var b strings.Builder
+	b.WriteString(s.insert)
+	args := make([]interface{}, 0, len(rows)*len(s.columns))
+	for r, row := range rows {
+		if r > 0 {
+			b.WriteString(", ")
+		}
+		b.WriteByte('(')
+		for i, v := range row {
+			if i > 0 {
+				b.WriteString(", ")
+			}
+			args = append(args, v)
+			fmt.Fprintf(&b, "$%d", len(args))
+		}
+		b.WriteByte(')')
+	}
+	b.WriteString(s.suffix)
+	return b.String(), args
+}
+
+// quoteIdent quotes a Postgres identifier
+func quoteIdent(name string) string {
+	return `"` + strings.ReplaceAll(name, `"`, `""`) + `"`
+}
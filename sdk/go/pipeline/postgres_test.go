@@ -0,0 +1,126 @@
+package pipeline
+
+import (
+	"reflect"
+	"testing"
+)
+
+func newTestPostgresSink(t *testing.T, cfg PostgresConfig) *PostgresSink {
+	t.Helper()
+	s, err := NewPostgresSink(nil, cfg)
+	if err != nil {
+		t.Fatalf("NewPostgresSink: %v", err)
+	}
+	return s
+}
+
+func TestPostgresSinkRows(t *testing.T) {
+	s := newTestPostgresSink(t, PostgresConfig{
+		Table:         "jobs",
+		Columns:       []Column{FieldColumn("id", "id"), FieldColumn("title", "title")},
+		ConflictKey:   []string{"id"},
+		RowPerElement: true,
+	})
+	tests := []struct {
+		name  string
+		value interface{}
+		want  [][]interface{}
+	}{
+		{
+			name: "distinct keys",
+			value: []interface{}{
+				map[string]interface{}{"id": "a", "title": "Go developer"},
+				map[string]interface{}{"id": "b", "title": "SRE"},
+			},
+			want: [][]interface{}{{"a", "Go developer"}, {"b", "SRE"}},
+		},
+		{
+			name: "last row of a key wins",
+			value: []interface{}{
+				map[string]interface{}{"id": "a", "title": "Go developer"},
+				map[string]interface{}{"id": "b", "title": "SRE"},
+				map[string]interface{}{"id": "a", "title": "Senior Go developer"},
+			},
+			want: [][]interface{}{{"a", "Senior Go developer"}, {"b", "SRE"}},
+		},
+		{
+			name: "keys of different types differ",
+			value: []interface{}{
+				map[string]interface{}{"id": "1", "title": "string key"},
+				map[string]interface{}{"id": float64(1), "title": "number key"},
+			},
+			want: [][]interface{}{{"1", "string key"}, {float64(1), "number key"}},
+		},
+		{
+			name:  "empty slice",
+			value: []interface{}{},
+			want:  nil,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rows, err := s.rows(&Item{Value: tt.value})
+			if err != nil {
+				t.Fatalf("rows: %v", err)
+			}
+			if !reflect.DeepEqual(rows, tt.want) {
+				t.Errorf("rows = %v, want %v", rows, tt.want)
+			}
+		})
+	}
+
+	if _, err := s.rows(&Item{Value: map[string]interface{}{"id": "a"}}); err == nil {
+		t.Error("rows of an object with RowPerElement succeeded, want an error")
+	}
+}
+
+func TestPostgresSinkStatement(t *testing.T) {
+	s := newTestPostgresSink(t, PostgresConfig{
+		Table:       "public.jobs",
+		Columns:     []Column{FieldColumn("id", "id"), FieldColumn("title", "title")},
+		ConflictKey: []string{"id"},
+	})
+	query, args := s.statement([][]interface{}{{"a", "Go developer"}, {"b", "SRE"}})
+	want := `INSERT INTO "public"."jobs" ("id", "title") VALUES ($1, $2), ($3, $4) ON CONFLICT ("id") DO UPDATE SET "title" = EXCLUDED."title"`
+	if query != want {
+		t.Errorf("query =\n%s\nwant\n%s", query, want)
+	}
+	if wantArgs := []interface{}{"a", "Go developer", "b", "SRE"}; !reflect.DeepEqual(args, wantArgs) {
+		t.Errorf("args = %v, want %v", args, wantArgs)
+	}
+
+	keyOnly := newTestPostgresSink(t, PostgresConfig{Table: "seen", Columns: []Column{RequestIDColumn("request_id")}})
+	query, _ = keyOnly.statement([][]interface{}{{"req-1"}})
+	if want := `INSERT INTO "seen" ("request_id") VALUES ($1) ON CONFLICT ("request_id") DO NOTHING`; query != want {
+		t.Errorf("query =\n%s\nwant\n%s", query, want)
+	}
+}
+
+func TestPostgresSinkStatements(t *testing.T) {
+	s := newTestPostgresSink(t, PostgresConfig{Table: "scrape_results"})
+	perStatement := maxPostgresParams / len(s.columns)
+	tests := []struct {
+		rows     int
+		wantArgs []int
+	}{
+		{1, []int{4}},
+		{perStatement, []int{perStatement * 4}},
+		{perStatement + 1, []int{perStatement * 4, 4}},
+	}
+	for _, tt := range tests {
+		rows := make([][]interface{}, tt.rows)
+		for i := range rows {
+			rows[i] = []interface{}{i, nil, nil, "{}"}
+		}
+		var gotArgs []int
+		for _, args := range s.statements(rows) {
+			if len(args) > maxPostgresParams {
+				t.Errorf("statement has %d parameters, more than Postgres takes", len(args))
+			}
+			gotArgs = append(gotArgs, len(args))
+		}
+		if !reflect.DeepEqual(gotArgs, tt.wantArgs) {
+			t.Errorf("%d rows: statements have %v arguments, want %v", tt.rows, gotArgs, tt.wantArgs)
+		}
+	}
+}
@@ -0,0 +1,120 @@
+package pipeline
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"strconv"
+)
+
+// Message headers a PublisherSink sets
+const (
+	HeaderContentType = "Content-Type"
+	HeaderRequestID   = "Scrapeapi-Request-Id"
+	// HeaderElement is the index of the element a PublishElements message
+	// holds, so consumers can tell apart messages sharing a key
+	HeaderElement = "Scrapeapi-Element"
+)
+
+// Message is one message for a broker such as Kafka or NATS
+type Message struct {
+	// Topic is the Kafka topic or NATS subject
+	Topic string
+	// Key is the job's request ID, so messages of one job share a partition
+	Key     []byte
+	Value   []byte
+	Headers map[string]string
+}
+
+// Publisher sends messages to a broker. The SDK does not depend on a
+// broker client; wrap the producer you use to implement it. A publisher
+// that also has a Flush(ctx) error method is flushed when the sink is closed.
+type Publisher interface {
+	Publish(ctx context.Context, msg Message) error
+}
+
+// Payload is what a PublisherSink puts in its messages
+type Payload int
+
+const (
+	// PublishRecord sends the item's Record
+	PublishRecord Payload = iota
+	// PublishResponse sends the whole ScrapeResponse, as the API returned it
+	PublishResponse
+	// PublishElements sends one message per element of a slice value, such
+	// as the listings decoded from one page
+	PublishElements
+)
+
+// PublisherConfig configures a PublisherSink
+type PublisherConfig struct {
+	// Topic is the topic or subject every message is published to
+	Topic string
+	// Payload is what the messages hold; PublishRecord by default
+	Payload Payload
+}
+
+// PublisherSink publishes every item as JSON messages keyed by request ID
+type PublisherSink struct {
+	pub Publisher
+	cfg PublisherConfig
+}
+
+// NewPublisherSink returns a sink publishing with pub
+func NewPublisherSink(pub Publisher, cfg PublisherConfig) (*PublisherSink, error) {
+	if cfg.Topic == "" {
+		return nil, errors.New("publisher sink: topic is required")
+	}
+	return &PublisherSink{pub: pub, cfg: cfg}, nil
+}
+
+func (s *PublisherSink) Write(ctx context.Context, item *Item) error {
+	rec := NewRecord(item)
+	var values []interface{}
+	switch s.cfg.Payload {
+	case PublishResponse:
+		values = []interface{}{item.Response}
+	case PublishElements:
+		v := reflect.ValueOf(item.Value)
+		if v.Kind() != reflect.Slice && v.Kind() != reflect.Array {
+			return fmt.Errorf("publisher sink: value is %T, want a slice to publish its elements", item.Value)
+		}
+		for i := 0; i < v.Len(); i++ {
+			values = append(values, v.Index(i).Interface())
+		}
+	default:
+		values = []interface{}{rec}
+	}
+
+	for i, v := range values {
+		data, err := json.Marshal(v)
+		if err != nil {
+			return fmt.Errorf("encode message: %w", err)
+		}
+		msg := Message{
+			Topic: s.cfg.Topic,
+			Key:   []byte(rec.RequestID),
+			Value: data,
+			Headers: map[string]string{
+				HeaderContentType: "application/json",
+				HeaderRequestID:   rec.RequestID,
+			},
+		}
+		if s.cfg.Payload == PublishElements {
+			msg.Headers[HeaderElement] = strconv.Itoa(i)
+		}
+		if err := s.pub.Publish(ctx, msg); err != nil {
+			return fmt.Errorf("publish to %s: %w", s.cfg.Topic, err)
+		}
+	}
+	return nil
+}
+
+func (s *PublisherSink) Close(ctx context.Context) error {
+	if f, ok := s.pub.(interface{ Flush(context.Context) error }); ok {
+		return f.Flush(ctx)
+	}
+	return nil
+}
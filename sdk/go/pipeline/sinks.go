@@ -0,0 +1,211 @@
+package pipeline
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"sync"
+	"text/template"
+	"time"
+)
+
+// Record is how the sinks in this package store an item: its value along
+// with what identifies the job it came from
+type Record struct {
+	RequestID  string      `json:"request_id"`
+	WebsiteURL string      `json:"website_url,omitempty"`
+	Tags       []string    `json:"tags,omitempty"`
+	CreatedAt  *time.Time  `json:"created_at,omitempty"`
+	Result     interface{} `json:"result"`
+}
+
+// NewRecord returns the record for item
+func NewRecord(item *Item) Record {
+	rec := Record{Result: item.Value}
+	if item.Response != nil {
+		rec.RequestID = item.Response.RequestID
+		rec.Tags = item.Response.Tags
+		rec.CreatedAt = item.Response.CreatedAt
+		if item.Response.WebsiteURL != nil {
+			rec.WebsiteURL = *item.Response.WebsiteURL
+		}
+	}
+	if rec.WebsiteURL == "" && item.Request != nil && item.Request.WebsiteURL != nil {
+		rec.WebsiteURL = *item.Request.WebsiteURL
+	}
+	return rec
+}
+
+// NDJSONSink writes every item as one JSON Record per line. Unlike most
+// sinks it is safe for concurrent use, so Pool consumers can share one.
+type NDJSONSink struct {
+	mu     sync.Mutex
+	w      *bufio.Writer
+	closer io.Closer
+}
+
+// NewNDJSONSink returns a sink writing to w, which Close flushes but leaves open
+func NewNDJSONSink(w io.Writer) *NDJSONSink {
+	return &NDJSONSink{w: bufio.NewWriter(w)}
+}
+
+// CreateNDJSONFile returns a sink writing to a new file at path, truncating
+// any existing one; Close closes the file
+func CreateNDJSONFile(path string) (*NDJSONSink, error) {
+	f, err := os.Create(path)
+	if err != nil {
+		return nil, err
+	}
+	return &NDJSONSink{w: bufio.NewWriter(f), closer: f}, nil
+}
+
+func (s *NDJSONSink) Write(ctx context.Context, item *Item) error {
+	line, err := json.Marshal(NewRecord(item))
+	if err != nil {
+		return fmt.Errorf("encode record: %w", err)
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, err := s.w.Write(append(line, '\n')); err != nil {
+		return err
+	}
+	return nil
+}
+
+func (s *NDJSONSink) Close(ctx context.Context) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	err := s.w.Flush()
+	if s.closer != nil {
+		err = errors.Join(err, s.closer.Close())
+		s.closer = nil
+	}
+	return err
+}
+
+// ObjectStore stores objects by key, like an S3 bucket. The SDK does not
+// depend on a cloud SDK; wrap the client of your store to implement it.
+type ObjectStore interface {
+	PutObject(ctx context.Context, key string, body []byte, contentType string) error
+}
+
+// DirStore is an ObjectStore keeping every object in a file under the
+// directory, with slashes in keys as subdirectories
+type DirStore string
+
+func (d DirStore) PutObject(ctx context.Context, key string, body []byte, contentType string) error {
+	name := filepath.FromSlash(key)
+	if !filepath.IsLocal(name) {
+		return fmt.Errorf("key %q is outside the directory", key)
+	}
+	path := filepath.Join(string(d), name)
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	// Write and rename, so readers never see half an object
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".put-*")
+	if err != nil {
+		return err
+	}
+	_, err = tmp.Write(body)
+	if err := errors.Join(err, tmp.Close()); err != nil {
+		os.Remove(tmp.Name())
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
+
+// DefaultObjectKey names objects after the job's request ID
+const DefaultObjectKey = "{{.RequestID}}.json"
+
+// ObjectKey is what an object key template is executed with
+type ObjectKey struct {
+	RequestID string
+	// URL is the scraped URL as is
+	URL string
+	// Host is the host of URL, e.g. "example.com"
+	Host string
+	// Slug is URL without its scheme, made safe for keys and file names,
+	// e.g. "example.com_jobs_page_2" for https://example.com/jobs?page=2
+	Slug string
+	// Date is the day the job was created, as 2006-01-02
+	Date string
+}
+
+// ObjectSink stores every item as a JSON Record in its own object
+type ObjectSink struct {
+	store ObjectStore
+	key   *template.Template
+}
+
+// NewObjectSink returns a sink putting every item into store under the key
+// keyTemplate renders to, a text/template over an ObjectKey such as
+// "jobs/{{.Date}}/{{.Slug}}-{{.RequestID}}.json" (DefaultObjectKey if empty)
+func NewObjectSink(store ObjectStore, keyTemplate string) (*ObjectSink, error) {
+	if keyTemplate == "" {
+		keyTemplate = DefaultObjectKey
+	}
+	key, err := template.New("key").Option("missingkey=error").Parse(keyTemplate)
+	if err != nil {
+		return nil, fmt.Errorf("object key template: %w", err)
+	}
+	return &ObjectSink{store: store, key: key}, nil
+}
+
+func (s *ObjectSink) Write(ctx context.Context, item *Item) error {
+	rec := NewRecord(item)
+	body, err := json.Marshal(rec)
+	if err != nil {
+		return fmt.Errorf("encode record: %w", err)
+	}
+
+	k := ObjectKey{RequestID: rec.RequestID, URL: rec.WebsiteURL, Slug: Slug(rec.WebsiteURL), Date: time.Now().UTC().Format(time.DateOnly)}
+	if u, err := url.Parse(rec.WebsiteURL); err == nil {
+		k.Host = u.Host
+	}
+	if rec.CreatedAt != nil {
+		k.Date = rec.CreatedAt.UTC().Format(time.DateOnly)
+	}
+	var b strings.Builder
+	if err := s.key.Execute(&b, k); err != nil {
+		return fmt.Errorf("object key: %w", err)
+	}
+	if b.Len() == 0 {
+		return fmt.Errorf("object key template rendered an empty key")
+	}
+	return s.store.PutObject(ctx, b.String(), body, "application/json")
+}
+
+func (s *ObjectSink) Close(ctx context.Context) error {
+	return nil
+}
+
+var unsafeKeyChars = regexp.MustCompile(`[^A-Za-z0-9._-]+`)
+
+// Slug makes a URL safe for object keys and file names, e.g.
+// "example.com_jobs" for https://example.com/jobs
+func Slug(raw string) string {
+	name := raw
+	if u, err := url.Parse(raw); err == nil && u.Host != "" {
+		name = u.Host + u.Path
+		if u.RawQuery != "" {
+			name += "_" + u.RawQuery
+		}
+	}
+	name = strings.Trim(unsafeKeyChars.ReplaceAllString(name, "_"), "_.")
+	if len(name) > 120 {
+		name = name[:120]
+	}
+	if name == "" {
+		name = "result"
+	}
+	return name
+}
@@ -0,0 +1,25 @@
+package pipeline
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestSlug(t *testing.T) {
+	tests := []struct {
+		in   string
+		want string
+	}{
+		{"https://example.com/jobs", "example.com_jobs"},
+		{"https://example.com/jobs/?page=2&sort=new", "example.com_jobs__page_2_sort_new"},
+		{"https://example.com/", "example.com"},
+		{"not a url", "not_a_url"},
+		{"", "result"},
+		{"https://example.com/" + strings.Repeat("a", 200), "example.com_" + strings.Repeat("a", 108)},
+	}
+	for _, tt := range tests {
+		if got := Slug(tt.in); got != tt.want {
+			t.Errorf("Slug(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
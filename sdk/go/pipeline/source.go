@@ -0,0 +1,43 @@
+package pipeline
+
+import (
+	scrapeapi "github.com/dir01/scrapeapi/sdk/go"
+)
+
+// FromRequests returns a source of the given requests
+func FromRequests(reqs ...*scrapeapi.ScrapeRequest) Source {
+	return func(yield func(*scrapeapi.ScrapeRequest, error) bool) {
+		for _, req := range reqs {
+			if !yield(req, nil) {
+				return
+			}
+		}
+	}
+}
+
+// FromURLs returns a source of copies of base, one per URL, each with its
+// WebsiteURL set to that URL
+func FromURLs(base *scrapeapi.ScrapeRequest, urls ...string) Source {
+	return func(yield func(*scrapeapi.ScrapeRequest, error) bool) {
+		for _, u := range urls {
+			req := *base
+			req.WebsiteURL = scrapeapi.String(u)
+			if !yield(&req, nil) {
+				return
+			}
+		}
+	}
+}
+
+// FromChannel returns a source of the requests received on ch, which ends
+// once ch is closed. The sender should stop once Run returns, since
+// nothing receives from ch after that.
+func FromChannel(ch <-chan *scrapeapi.ScrapeRequest) Source {
+	return func(yield func(*scrapeapi.ScrapeRequest, error) bool) {
+		for req := range ch {
+			if !yield(req, nil) {
+				return
+			}
+		}
+	}
+}
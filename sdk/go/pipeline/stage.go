@@ -0,0 +1,89 @@
+package pipeline
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+// ErrSkip is returned by a stage to drop an item without failing it
+var ErrSkip = errors.New("skip item")
+
+// Stage is one step of a pipeline. Apply may replace item.Value; an error
+// fails the item at this stage, unless it is ErrSkip.
+type Stage struct {
+	// Name identifies the stage in Item.Stage
+	Name  string
+	Apply func(ctx context.Context, item *Item) error
+}
+
+// StageFunc returns a stage with the given name
+func StageFunc(name string, apply func(ctx context.Context, item *Item) error) Stage {
+	return Stage{Name: name, Apply: apply}
+}
+
+// Decode decodes the job result into T, with the strictness of
+// ScrapeResponse.DecodeResult, and makes it the item's value
+func Decode[T any]() Stage {
+	return StageFunc("decode", func(ctx context.Context, item *Item) error {
+		var v T
+		if err := item.Response.DecodeResult(&v); err != nil {
+			return err
+		}
+		item.Value = v
+		return nil
+	})
+}
+
+// Validate fails items whose value check rejects
+func Validate[T any](check func(T) error) Stage {
+	return StageFunc("validate", func(ctx context.Context, item *Item) error {
+		v, err := valueOf[T](item)
+		if err != nil {
+			return err
+		}
+		return check(v)
+	})
+}
+
+// Map replaces the item's value with what fn makes of it, e.g. to
+// normalize it or convert it to the type a sink takes
+func Map[T, U any](fn func(T) (U, error)) Stage {
+	return StageFunc("map", func(ctx context.Context, item *Item) error {
+		v, err := valueOf[T](item)
+		if err != nil {
+			return err
+		}
+		out, err := fn(v)
+		if err != nil {
+			return err
+		}
+		item.Value = out
+		return nil
+	})
+}
+
+// Filter drops items whose value keep returns false for
+func Filter[T any](keep func(T) bool) Stage {
+	return StageFunc("filter", func(ctx context.Context, item *Item) error {
+		v, err := valueOf[T](item)
+		if err != nil {
+			return err
+		}
+		if !keep(v) {
+			return ErrSkip
+		}
+		return nil
+	})
+}
+
+// valueOf returns the item's value as a T, which an earlier stage must
+// have made it
+func valueOf[T any](item *Item) (T, error) {
+	v, ok := item.Value.(T)
+	if !ok {
+		var zero T
+		return zero, fmt.Errorf("value is %T, want %T; decode it first", item.Value, zero)
+	}
+	return v, nil
+}
@@ -0,0 +1,51 @@
+package scrapeapi
+
+import (
+	"math"
+	"math/rand/v2"
+	"time"
+)
+
+// Default adaptive polling schedule: poll quickly while short jobs finish,
+// then back off so long jobs don't hammer the API
+const (
+	defaultPollMin    = 500 * time.Millisecond
+	defaultPollMax    = 10 * time.Second
+	defaultPollFactor = 1.5
+	pollJitter        = 0.2
+)
+
+// WithPollBackoff polls adaptively: the first poll happens after min, every
+// following delay grows by factor up to max, and each delay is jittered to
+// spread out concurrent waiters. This is the default schedule
+// (500ms, 10s, 1.5) unless a fixed interval is given.
+func WithPollBackoff(min, max time.Duration, factor float64) WaitOption {
+	return func(cfg *waitConfig) {
+		cfg.pollInterval = 0
+		cfg.pollMin = min
+		cfg.pollMax = max
+		cfg.pollFactor = factor
+	}
+}
+
+// pollDelay returns the delay before the nth poll, starting at 0
+func (cfg *waitConfig) pollDelay(n int) time.Duration {
+	if cfg.pollInterval > 0 {
+		return cfg.pollInterval
+	}
+
+	minDelay, maxDelay, factor := cfg.pollMin, cfg.pollMax, cfg.pollFactor
+	if minDelay <= 0 {
+		minDelay = defaultPollMin
+	}
+	if maxDelay <= 0 {
+		maxDelay = defaultPollMax
+	}
+	if factor < 1 {
+		factor = defaultPollFactor
+	}
+
+	d := math.Min(float64(minDelay)*math.Pow(factor, float64(n)), float64(maxDelay))
+	d += d * pollJitter * (2*rand.Float64() - 1)
+	return time.Duration(d)
+}
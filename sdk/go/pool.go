@@ -0,0 +1,198 @@
+package scrapeapi
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// defaultPoolConcurrency is how many jobs a Pool runs at once unless WithConcurrency is given
+const defaultPoolConcurrency = 8
+
+// ErrPoolClosed is returned by Submit after Close
+var ErrPoolClosed = errors.New("pool is closed")
+
+// PoolResult is the outcome of one request submitted to a Pool
+type PoolResult struct {
+	// Request is the request as it was submitted
+	Request *ScrapeRequest
+	// Response is the finished job; nil if Err is set
+	Response *ScrapeResponse
+	// Err is why the job did not complete after all its attempts
+	Err error
+	// Attempts is how many jobs were started for Request
+	Attempts int
+}
+
+// PoolOption is a functional option for configuring a Pool
+type PoolOption func(*Pool)
+
+// WithConcurrency sets how many jobs a Pool runs at once (8 by default)
+func WithConcurrency(n int) PoolOption {
+	return func(p *Pool) {
+		if n > 0 {
+			p.concurrency = n
+		}
+	}
+}
+
+// WithJobRetries makes a Pool rerun a job that failed for a reason worth
+// retrying, such as a failed fetch, up to retries more times. It waits
+// backoff before the first retry and twice as long before each next one.
+//
+// This retries whole jobs, unlike WithRetryPolicy, which retries single API calls.
+func WithJobRetries(retries int, backoff time.Duration) PoolOption {
+	return func(p *Pool) {
+		p.retries = retries
+		p.backoff = backoff
+	}
+}
+
+// WithPoolWaitOptions sets the WaitOptions every job of a Pool is waited for with
+func WithPoolWaitOptions(opts ...WaitOption) PoolOption {
+	return func(p *Pool) {
+		p.waitOpts = opts
+	}
+}
+
+// Pool runs ScrapeAndWait for many requests with bounded concurrency and
+// delivers every outcome on one channel, for fanning out over hundreds of
+// URLs without hand-written semaphores.
+//
+//	pool := scrapeapi.NewPool(ctx, client, scrapeapi.WithConcurrency(10), scrapeapi.WithJobRetries(2, time.Second))
+//	for _, u := range urls {
+//		pool.Submit(&scrapeapi.ScrapeRequest{Graph: scrapeapi.GraphSmart, UserPrompt: prompt, WebsiteURL: scrapeapi.String(u)})
+//	}
+//	pool.Close()
+//	for result := range pool.Results() {
+//		if result.Err != nil {
+//			log.Printf("%s: %v", *result.Request.WebsiteURL, result.Err)
+//		}
+//	}
+//
+// Every submitted request yields exactly one result, in the order the jobs
+// finish, and Results is closed once the pool is closed and all of them
+// are delivered. Results must be drained, or the pool's goroutines never
+// exit. Submit does not block, so requests can all be submitted before
+// Results is read.
+type Pool struct {
+	client      ScrapeClient
+	ctx         context.Context
+	concurrency int
+	retries     int
+	backoff     time.Duration
+	waitOpts    []WaitOption
+
+	sem     chan struct{}
+	results chan PoolResult
+	wg      sync.WaitGroup
+
+	mu     sync.Mutex
+	closed bool
+}
+
+// NewPool returns a pool running jobs with client until ctx ends. Jobs
+// still waiting for a slot when ctx ends are reported with ctx's error.
+func NewPool(ctx context.Context, client ScrapeClient, opts ...PoolOption) *Pool {
+	p := &Pool{
+		client:      client,
+		ctx:         ctx,
+		concurrency: defaultPoolConcurrency,
+		results:     make(chan PoolResult),
+	}
+	for _, opt := range opts {
+		opt(p)
+	}
+	p.sem = make(chan struct{}, p.concurrency)
+	return p
+}
+
+// Submit queues req to run once a slot is free. It fails only after Close.
+func (p *Pool) Submit(req *ScrapeRequest) error {
+	p.mu.Lock()
+	if p.closed {
+		p.mu.Unlock()
+		return ErrPoolClosed
+	}
+	p.wg.Add(1)
+	p.mu.Unlock()
+
+	go func() {
+		defer p.wg.Done()
+
+		result := PoolResult{Request: req}
+		select {
+		case p.sem <- struct{}{}:
+			result.Response, result.Attempts, result.Err = p.run(req)
+			// Free the slot before delivering, so a slow reader doesn't hold up other jobs
+			<-p.sem
+		case <-p.ctx.Done():
+			result.Err = p.ctx.Err()
+		}
+		p.results <- result
+	}()
+	return nil
+}
+
+// Results returns the channel the outcomes are delivered on
+func (p *Pool) Results() <-chan PoolResult {
+	return p.results
+}
+
+// Close stops the pool accepting requests. Submitted requests still run,
+// and Results is closed once their outcomes are delivered.
+func (p *Pool) Close() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.closed {
+		return
+	}
+	p.closed = true
+	go func() {
+		p.wg.Wait()
+		close(p.results)
+	}()
+}
+
+// run runs req, retrying as configured
+func (p *Pool) run(req *ScrapeRequest) (*ScrapeResponse, int, error) {
+	delay := p.backoff
+	for attempt := 1; ; attempt++ {
+		resp, err := p.client.ScrapeAndWait(p.ctx, req, p.waitOpts...)
+		if err == nil || attempt > p.retries || !retryableJob(err) {
+			return resp, attempt, err
+		}
+		if err := sleep(p.ctx, delay); err != nil {
+			return nil, attempt, err
+		}
+		delay *= 2
+	}
+}
+
+// retryableJob reports whether a job that ended with err may succeed if
+// run again: it failed on the server, or the API could not be reached.
+// Invalid requests, client errors, canceled jobs, exhausted budgets and
+// jobs that may still be running are not retried.
+func retryableJob(err error) bool {
+	switch {
+	case errors.Is(err, context.Canceled), errors.Is(err, context.DeadlineExceeded),
+		errors.Is(err, ErrWaitTimeout), errors.Is(err, ErrStreamEnded), errors.Is(err, ErrDisallowedByRobots):
+		return false
+	}
+
+	var validationErr *ValidationError
+	if errors.As(err, &validationErr) {
+		return false
+	}
+	var jobErr *JobError
+	if errors.As(err, &jobErr) {
+		return jobErr.Status == StatusFailed && jobErr.Code != ErrorCodeBudgetExceeded
+	}
+	var apiErr *APIError
+	if errors.As(err, &apiErr) && apiErr.StatusCode < http.StatusInternalServerError {
+		return apiErr.StatusCode == http.StatusTooManyRequests || apiErr.StatusCode == http.StatusRequestTimeout
+	}
+	return true
+}
@@ -0,0 +1,29 @@
+package scrapeapi
+
+// Stage is the phase a running job is in
+type Stage string
+
+// Job stages reported by the API
+const (
+	StageFetching   Stage = "fetching"
+	StageRendering  Stage = "rendering"
+	StageExtracting Stage = "extracting"
+)
+
+// ProgressFunc is called with every job update observed while waiting
+type ProgressFunc func(resp *ScrapeResponse)
+
+// WithProgress calls fn with every job update observed while waiting,
+// including the final one. With WaitForBatch, fn is called concurrently.
+func WithProgress(fn ProgressFunc) WaitOption {
+	return func(cfg *waitConfig) {
+		cfg.onProgress = fn
+	}
+}
+
+// reportProgress hands resp to the configured progress callback, if any
+func (cfg *waitConfig) reportProgress(resp *ScrapeResponse) {
+	if cfg.onProgress != nil {
+		cfg.onProgress(resp)
+	}
+}
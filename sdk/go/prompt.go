@@ -0,0 +1,121 @@
+package scrapeapi
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"text/template"
+	"text/template/parse"
+)
+
+// Prompt is a reusable user prompt with text/template variables
+type Prompt struct {
+	tmpl *template.Template
+}
+
+// PromptTemplate parses a prompt template and panics if it is malformed, so
+// it can initialize package-level prompts like regexp.MustCompile.
+//
+//	var entityPrompt = scrapeapi.PromptTemplate("Extract {{.Entity}} from this page about {{.Topic}}")
+//
+//	req.UserPrompt, err = entityPrompt.Execute(map[string]string{"Entity": "job ads", "Topic": "hiring"})
+func PromptTemplate(text string) *Prompt {
+	p, err := ParsePrompt(text)
+	if err != nil {
+		panic("scrapeapi: " + err.Error())
+	}
+	return p
+}
+
+// ParsePrompt parses a prompt template, returning an error if it is malformed
+func ParsePrompt(text string) (*Prompt, error) {
+	// missingkey=error makes a variable absent from map data an error instead of "<no value>"
+	tmpl, err := template.New("prompt").Option("missingkey=error").Parse(text)
+	if err != nil {
+		return nil, fmt.Errorf("prompt template: %w", err)
+	}
+	return &Prompt{tmpl: tmpl}, nil
+}
+
+// Execute renders the prompt with data, a map or struct holding the
+// variables. It fails if a variable is missing from data or the prompt renders empty.
+func (p *Prompt) Execute(data interface{}) (string, error) {
+	if m, ok := data.(map[string]string); ok {
+		// Check up front so every missing variable is reported, not just the first
+		var missing []string
+		for _, name := range p.Variables() {
+			if _, ok := m[name]; !ok {
+				missing = append(missing, name)
+			}
+		}
+		if len(missing) > 0 {
+			return "", fmt.Errorf("prompt template: missing variables %s", strings.Join(missing, ", "))
+		}
+	}
+
+	var sb strings.Builder
+	if err := p.tmpl.Execute(&sb, data); err != nil {
+		return "", fmt.Errorf("prompt template: %w", err)
+	}
+	prompt := strings.TrimSpace(sb.String())
+	if prompt == "" {
+		return "", fmt.Errorf("prompt template: rendered an empty prompt")
+	}
+	return prompt, nil
+}
+
+// Variables returns the names of the top-level variables the prompt refers to, sorted
+func (p *Prompt) Variables() []string {
+	seen := map[string]bool{}
+	var walk func(node parse.Node)
+	walk = func(node parse.Node) {
+		switch n := node.(type) {
+		case *parse.ListNode:
+			if n == nil {
+				return
+			}
+			for _, child := range n.Nodes {
+				walk(child)
+			}
+		case *parse.ActionNode:
+			walk(n.Pipe)
+		case *parse.PipeNode:
+			if n == nil {
+				return
+			}
+			for _, cmd := range n.Cmds {
+				walk(cmd)
+			}
+		case *parse.CommandNode:
+			for _, arg := range n.Args {
+				walk(arg)
+			}
+		case *parse.FieldNode:
+			seen[n.Ident[0]] = true
+		case *parse.IfNode:
+			walk(n.Pipe)
+			walk(n.List)
+			walk(n.ElseList)
+		case *parse.RangeNode:
+			// Fields inside range and with are relative to their pipeline, not top-level
+			walk(n.Pipe)
+			walk(n.ElseList)
+		case *parse.WithNode:
+			walk(n.Pipe)
+			walk(n.ElseList)
+		}
+	}
+	walk(p.tmpl.Tree.Root)
+
+	names := make([]string, 0, len(seen))
+	for name := range seen {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// String returns the template text
+func (p *Prompt) String() string {
+	return p.tmpl.Tree.Root.String()
+}
@@ -0,0 +1,84 @@
+package scrapeapi
+
+import (
+	"context"
+	"math"
+	"sync"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// WithRateLimit caps the client's API calls at rps per second on average,
+// with up to burst calls at once after a quiet spell. The limit is shared
+// by every goroutine using the client and applies to each attempt of a
+// retried call, so batch submissions stay under the server's quota.
+// Calls wait for their turn until their context ends. Calls are not
+// limited by default.
+func WithRateLimit(rps float64, burst int) ClientOption {
+	return func(c *Client) {
+		if rps <= 0 {
+			c.limiter = nil
+			return
+		}
+		c.limiter = newRateLimiter(rps, max(burst, 1))
+	}
+}
+
+// rateLimiter is a token bucket. Tokens may go negative, so callers
+// waiting for one are served in the order they arrived.
+type rateLimiter struct {
+	rate  float64
+	burst float64
+
+	mu     sync.Mutex
+	tokens float64
+	last   time.Time
+}
+
+func newRateLimiter(rps float64, burst int) *rateLimiter {
+	return &rateLimiter{rate: rps, burst: float64(burst), tokens: float64(burst), last: time.Now()}
+}
+
+// reserve takes a token and returns how long to wait until it is due
+func (l *rateLimiter) reserve() time.Duration {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	now := time.Now()
+	l.tokens = math.Min(l.burst, l.tokens+now.Sub(l.last).Seconds()*l.rate)
+	l.last = now
+	l.tokens--
+	if l.tokens >= 0 {
+		return 0
+	}
+	return time.Duration(-l.tokens / l.rate * float64(time.Second))
+}
+
+// cancel returns a token reserved by a caller that gave up waiting
+func (l *rateLimiter) cancel() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.tokens = math.Min(l.burst, l.tokens+1)
+}
+
+// waitRateLimit blocks until the client's rate limit allows another call
+func (c *Client) waitRateLimit(ctx context.Context) error {
+	if c.limiter == nil {
+		return nil
+	}
+	delay := c.limiter.reserve()
+	if delay == 0 {
+		return nil
+	}
+
+	trace.SpanFromContext(ctx).AddEvent("rate_limit.wait", trace.WithAttributes(
+		attribute.String("rate_limit.delay", delay.String()),
+	))
+	c.logger.DebugContext(ctx, "waiting for rate limit", "delay", delay)
+	if err := sleep(ctx, delay); err != nil {
+		c.limiter.cancel()
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,199 @@
+package scrapeapi
+
+import (
+	"bytes"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// redacted replaces secret values in debug output
+const redacted = "[REDACTED]"
+
+// sensitiveFields are payload fields holding credentials, by JSON name
+var sensitiveFields = map[string]bool{
+	"api_key":           true,
+	"access_key_id":     true,
+	"password":          true,
+	"bearer_token":      true,
+	"secret_access_key": true,
+	"session_token":     true,
+}
+
+// sensitiveParams are URL query parameters holding credentials
+var sensitiveParams = map[string]bool{
+	"api_key":      true,
+	"apikey":       true,
+	"key":          true,
+	"token":        true,
+	"access_token": true,
+	"password":     true,
+	"signature":    true,
+	"sig":          true,
+}
+
+// sensitiveHeaders are headers holding credentials, in canonical form
+var sensitiveHeaders = map[string]bool{
+	"Authorization":       true,
+	"Proxy-Authorization": true,
+	"Cookie":              true,
+	"Set-Cookie":          true,
+	"X-Api-Key":           true,
+}
+
+// redactHeader returns a copy of h with the values of credential headers masked
+func redactHeader(h http.Header) http.Header {
+	out := make(http.Header, len(h))
+	for key, values := range h {
+		if sensitiveHeaders[http.CanonicalHeaderKey(key)] {
+			values = []string{redacted}
+		}
+		out[key] = values
+	}
+	return out
+}
+
+// redactJSON masks credentials in a JSON payload: API keys, passwords and
+// tokens, cookie values, credential headers, credentials in URLs and the
+// text typed into pages by fill actions and form submissions.
+// Data that is not JSON is returned unchanged.
+func redactJSON(data []byte) []byte {
+	var v interface{}
+	if err := json.Unmarshal(data, &v); err != nil {
+		return data
+	}
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	// Keep URLs readable; the output is for humans, not HTML pages
+	enc.SetEscapeHTML(false)
+	if err := enc.Encode(redactValue(v)); err != nil {
+		return data
+	}
+	return bytes.TrimSuffix(buf.Bytes(), []byte("\n"))
+}
+
+func redactValue(v interface{}) interface{} {
+	switch val := v.(type) {
+	case map[string]interface{}:
+		// Fill actions type logins and passwords into the page
+		fill := val["type"] == string(ActionFill)
+		for key, field := range val {
+			switch s, isString := field.(string); {
+			case sensitiveFields[key] && isString && s != "":
+				val[key] = redacted
+			case fill && key == "text" && isString && s != "":
+				val[key] = redacted
+			case key == "submit_form":
+				val[key] = redactForm(field)
+			case isString && (key == "url" || strings.HasSuffix(key, "_url")):
+				val[key] = redactURL(s)
+			case key == "headers":
+				val[key] = redactHeaderMap(field)
+			case key == "cookies":
+				val[key] = redactCookies(field)
+			default:
+				val[key] = redactValue(field)
+			}
+		}
+	case []interface{}:
+		for i, item := range val {
+			val[i] = redactValue(item)
+		}
+	}
+	return v
+}
+
+func redactHeaderMap(v interface{}) interface{} {
+	headers, ok := v.(map[string]interface{})
+	if !ok {
+		return v
+	}
+	for name := range headers {
+		if sensitiveHeaders[http.CanonicalHeaderKey(name)] {
+			headers[name] = redacted
+		}
+	}
+	return headers
+}
+
+// redactForm masks the values a form submission types into the page
+func redactForm(v interface{}) interface{} {
+	form, ok := v.(map[string]interface{})
+	if !ok {
+		return redactValue(v)
+	}
+	if fields, ok := form["fields"].(map[string]interface{}); ok {
+		for sel := range fields {
+			fields[sel] = redacted
+		}
+	}
+	for key, field := range form {
+		if key != "fields" {
+			form[key] = redactValue(field)
+		}
+	}
+	return form
+}
+
+func redactCookies(v interface{}) interface{} {
+	cookies, ok := v.([]interface{})
+	if !ok {
+		return redactValue(v)
+	}
+	for _, c := range cookies {
+		if cookie, ok := c.(map[string]interface{}); ok {
+			if _, has := cookie["value"]; has {
+				cookie["value"] = redacted
+			}
+		}
+	}
+	return cookies
+}
+
+// redactURL masks the password of a URL with user info, e.g. a proxy URL,
+// and the values of query parameters that commonly carry credentials
+func redactURL(raw string) string {
+	if !strings.ContainsAny(raw, "@?") {
+		return raw
+	}
+	u, err := url.Parse(raw)
+	if err != nil {
+		return raw
+	}
+
+	if u.RawQuery != "" {
+		query := u.Query()
+		changed := false
+		for name := range query {
+			if sensitiveParams[strings.ToLower(name)] {
+				query[name] = []string{redacted}
+				changed = true
+			}
+		}
+		if changed {
+			u.RawQuery = query.Encode()
+		}
+	}
+	return u.Redacted()
+}
+
+// LogValue makes slog log the request as JSON with credentials masked, so a
+// request can be logged without leaking API keys, passwords or cookies
+func (r *ScrapeRequest) LogValue() slog.Value {
+	return redactedLogValue(r)
+}
+
+// LogValue makes slog log the LLM configuration with its API key and cloud credentials masked
+func (l *LLMConfig) LogValue() slog.Value {
+	return redactedLogValue(l)
+}
+
+func redactedLogValue(v interface{}) slog.Value {
+	raw, err := json.Marshal(v)
+	if err != nil {
+		return slog.StringValue(redacted)
+	}
+	return slog.StringValue(string(redactJSON(raw)))
+}
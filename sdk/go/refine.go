@@ -0,0 +1,44 @@
+package scrapeapi
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// refineRequest is the body of POST /v1/scrape/{id}/refine
+type refineRequest struct {
+	UserPrompt   string      `json:"user_prompt"`
+	OutputSchema interface{} `json:"output_schema,omitempty"`
+}
+
+// RefineScrape starts a new job that re-runs extraction on the page content
+// already fetched by a completed job, with a follow-up prompt and optionally a
+// new schema, so the page is not fetched and rendered a second time. A nil
+// schema extracts without one. Wait for the returned job as for StartScrape.
+//
+//	refined, err := client.RefineScrape(ctx, resp.RequestID, "Also extract the benefits", scrapeapi.SchemaFor[JobWithBenefits]())
+func (c *Client) RefineScrape(ctx context.Context, requestID, followUpPrompt string, newSchema interface{}, opts ...RequestOption) (*ScrapeResponse, error) {
+	// Create a span for this operation
+	// If there's no existing span in context, this creates a new root span
+	ctx, span := c.tracer.Start(ctx, "scrapeapi.RefineScrape")
+	defer span.End()
+	span.SetAttributes(attrRequestID.String(requestID))
+
+	v := &validator{}
+	if strings.TrimSpace(followUpPrompt) == "" {
+		v.addf("user_prompt", "is required")
+	}
+	if err := v.err(); err != nil {
+		return nil, err
+	}
+
+	body := refineRequest{UserPrompt: followUpPrompt, OutputSchema: newSchema}
+	var scrapeResp ScrapeResponse
+	if err := c.do(ctx, http.MethodPost, "/v1/scrape/"+url.PathEscape(requestID)+"/refine", body, &scrapeResp, opts...); err != nil {
+		return nil, err
+	}
+
+	return &scrapeResp, nil
+}
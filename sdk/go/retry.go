@@ -0,0 +1,158 @@
+package scrapeapi
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"math"
+	mathrand "math/rand/v2"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// RetryPolicy configures how failed API calls are retried.
+// Network errors and responses with one of RetryableStatusCodes are retried
+// with exponential backoff until MaxAttempts is reached. Errors decoding a
+// response or building a request are never retried.
+//
+// A POST that failed in transit may still have started a job, so POSTs are
+// only retried when the server can recognize the repeat: StartScrape and
+// StartBatchScrape send an Idempotency-Key of their own, CancelScrape and
+// DryRun are safe to repeat,
+// and other calls are retried if given WithHeader("Idempotency-Key", key).
+type RetryPolicy struct {
+	// MaxAttempts is the total number of attempts, including the first one. Values below 2 disable retries.
+	MaxAttempts int
+	// InitialBackoff is the delay before the first retry
+	InitialBackoff time.Duration
+	// MaxBackoff caps the delay between attempts
+	MaxBackoff time.Duration
+	// Multiplier grows the delay after every attempt
+	Multiplier float64
+	// Jitter randomizes each delay by up to this fraction (0 to 1) to avoid synchronized retries
+	Jitter float64
+	// RetryableStatusCodes lists HTTP status codes that are retried
+	RetryableStatusCodes []int
+}
+
+// DefaultRetryPolicy returns a policy of 3 attempts with backoff from 200ms to 5s,
+// retrying 429, 502, 503 and 504 responses and network errors
+func DefaultRetryPolicy() RetryPolicy {
+	return RetryPolicy{
+		MaxAttempts:    3,
+		InitialBackoff: 200 * time.Millisecond,
+		MaxBackoff:     5 * time.Second,
+		Multiplier:     2,
+		Jitter:         0.2,
+		RetryableStatusCodes: []int{
+			http.StatusTooManyRequests,
+			http.StatusBadGateway,
+			http.StatusServiceUnavailable,
+			http.StatusGatewayTimeout,
+		},
+	}
+}
+
+// WithRetryPolicy enables retries of transient failures for all API calls.
+// Retries are disabled by default.
+func WithRetryPolicy(policy RetryPolicy) ClientOption {
+	return func(c *Client) {
+		c.retryPolicy = policy
+	}
+}
+
+// headerIdempotencyKey makes the server start at most one job for
+// submissions that carry the same key
+const headerIdempotencyKey = "Idempotency-Key"
+
+// withIdempotencyKey adds a fresh Idempotency-Key to opts unless they set one
+func withIdempotencyKey(opts []RequestOption) []RequestOption {
+	if newRequestConfig(opts).header.Get(headerIdempotencyKey) != "" {
+		return opts
+	}
+	key := make([]byte, 16)
+	if _, err := rand.Read(key); err != nil {
+		// Without a key the call is just not retried
+		return opts
+	}
+	return append(opts, WithHeader(headerIdempotencyKey, hex.EncodeToString(key)))
+}
+
+// transportError is a failure to exchange a request with the API at all,
+// such as a refused connection, as opposed to an error response
+type transportError struct {
+	err error
+}
+
+func (e *transportError) Error() string {
+	return "execute request: " + e.err.Error()
+}
+
+func (e *transportError) Unwrap() error {
+	return e.err
+}
+
+// shouldRetry reports whether a failed attempt is worth retrying
+func (p RetryPolicy) shouldRetry(err error) bool {
+	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return false
+	}
+
+	var transportErr *transportError
+	if errors.As(err, &transportErr) {
+		// Connection resets, refused connections and other transport failures
+		return true
+	}
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) {
+		// Malformed responses and requests fail the same way every time
+		return false
+	}
+	for _, code := range p.RetryableStatusCodes {
+		if apiErr.StatusCode == code {
+			return true
+		}
+	}
+	return false
+}
+
+// backoff returns the delay before the given retry (1 for the first retry)
+func (p RetryPolicy) backoff(retry int) time.Duration {
+	d := float64(p.InitialBackoff) * math.Pow(math.Max(p.Multiplier, 1), float64(retry-1))
+	if p.MaxBackoff > 0 && d > float64(p.MaxBackoff) {
+		d = float64(p.MaxBackoff)
+	}
+	if p.Jitter > 0 {
+		d -= d * math.Min(p.Jitter, 1) * mathrand.Float64()
+	}
+	return time.Duration(d)
+}
+
+// retryAfter parses a Retry-After header given in seconds or as an HTTP date
+func retryAfter(h http.Header) time.Duration {
+	v := h.Get("Retry-After")
+	if secs, err := strconv.Atoi(v); err == nil {
+		if secs <= 0 {
+			return 0
+		}
+		return time.Duration(secs) * time.Second
+	}
+	if t, err := http.ParseTime(v); err == nil {
+		return max(time.Until(t), 0)
+	}
+	return 0
+}
+
+// sleep waits for d or until ctx is done
+func sleep(ctx context.Context, d time.Duration) error {
+	t := time.NewTimer(d)
+	defer t.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-t.C:
+		return nil
+	}
+}
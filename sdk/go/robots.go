@@ -0,0 +1,322 @@
+package scrapeapi
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+// RobotsPolicy decides what StartScrape does with URLs disallowed by robots.txt
+type RobotsPolicy int
+
+// Robots policies for WithRobotsCheck
+const (
+	// RobotsWarn logs disallowed URLs at warn level and submits the job anyway
+	RobotsWarn RobotsPolicy = iota + 1
+	// RobotsRefuse fails with a *RobotsError instead of submitting the job
+	RobotsRefuse
+)
+
+// robotsCacheTTL is how long a fetched robots.txt is reused
+const robotsCacheTTL = time.Hour
+
+// ErrDisallowedByRobots matches every *RobotsError
+var ErrDisallowedByRobots = errors.New("disallowed by robots.txt")
+
+// RobotsError reports a URL that robots.txt does not allow to be fetched
+type RobotsError struct {
+	URL       string
+	UserAgent string
+}
+
+func (e *RobotsError) Error() string {
+	agent := e.UserAgent
+	if agent == "" {
+		agent = "*"
+	}
+	return fmt.Sprintf("%s is disallowed by robots.txt for user agent %q", e.URL, agent)
+}
+
+// Is makes errors.Is(err, ErrDisallowedByRobots) match
+func (e *RobotsError) Is(target error) bool {
+	return target == ErrDisallowedByRobots
+}
+
+// WithRobotsCheck makes StartScrape, StartBatchScrape and StreamScrape check the
+// target URLs against their sites' robots.txt before submitting a job.
+// Set ScrapeRequest.RespectRobotsTxt as well to have the server honour robots.txt
+// for pages it discovers itself, e.g. while crawling.
+func WithRobotsCheck(policy RobotsPolicy) ClientOption {
+	return func(c *Client) {
+		c.robots = &robotsChecker{policy: policy, cache: map[string]robotsEntry{}}
+	}
+}
+
+// RobotsAllowed fetches the robots.txt of rawURL's site and reports whether
+// userAgent may fetch rawURL. An empty userAgent matches the "*" group.
+func (c *Client) RobotsAllowed(ctx context.Context, userAgent, rawURL string) (bool, error) {
+	// Create a span for this operation
+	// If there's no existing span in context, this creates a new root span
+	ctx, span := c.tracer.Start(ctx, "scrapeapi.RobotsAllowed")
+	defer span.End()
+
+	rc := c.robots
+	if rc == nil {
+		rc = &robotsChecker{cache: map[string]robotsEntry{}}
+	}
+	return rc.allowed(ctx, c.robotsClient(), userAgent, rawURL)
+}
+
+// robotsClient returns the client robots.txt files are fetched with. They
+// come from third-party sites, so unlike HTTPClient it is not instrumented
+// and sends no trace context or baggage; only the timeout is shared.
+func (c *Client) robotsClient() *http.Client {
+	return &http.Client{Timeout: c.HTTPClient.Timeout}
+}
+
+// checkRobots applies the configured robots policy to the target URLs of req
+func (c *Client) checkRobots(ctx context.Context, req *ScrapeRequest, urls ...string) error {
+	if c.robots == nil {
+		return nil
+	}
+
+	userAgent := req.Headers["User-Agent"]
+	if userAgent == "" && req.Device != nil {
+		userAgent = req.Device.UserAgent
+	}
+
+	targets := append([]string(nil), urls...)
+	if req.WebsiteURL != nil {
+		targets = append(targets, *req.WebsiteURL)
+	}
+	if req.Document != nil {
+		targets = append(targets, req.Document.URL)
+	}
+	targets = append(targets, sourceURLs(req.Sources)...)
+
+	hc := c.robotsClient()
+	for _, target := range targets {
+		ok, err := c.robots.allowed(ctx, hc, userAgent, target)
+		if err != nil {
+			if c.robots.policy == RobotsRefuse {
+				return err
+			}
+			c.logger.WarnContext(ctx, "robots.txt check failed", "url", redactURL(target), "error", err)
+			continue
+		}
+		if ok {
+			continue
+		}
+		if c.robots.policy == RobotsRefuse {
+			return &RobotsError{URL: target, UserAgent: userAgent}
+		}
+		c.logger.WarnContext(ctx, "submitting URL disallowed by robots.txt", "url", redactURL(target), "user_agent", userAgent)
+	}
+	return nil
+}
+
+// robotsChecker fetches and caches robots.txt files per site
+type robotsChecker struct {
+	policy RobotsPolicy
+
+	mu    sync.Mutex
+	cache map[string]robotsEntry
+}
+
+type robotsEntry struct {
+	rules   *robotsRules
+	fetched time.Time
+}
+
+func (rc *robotsChecker) allowed(ctx context.Context, hc *http.Client, userAgent, rawURL string) (bool, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return false, fmt.Errorf("robots.txt: %w", err)
+	}
+	site := u.Scheme + "://" + u.Host
+
+	rc.mu.Lock()
+	entry, ok := rc.cache[site]
+	rc.mu.Unlock()
+
+	if !ok || time.Since(entry.fetched) > robotsCacheTTL {
+		rules, err := fetchRobots(ctx, hc, site)
+		if err != nil {
+			return false, err
+		}
+		entry = robotsEntry{rules: rules, fetched: time.Now()}
+		rc.mu.Lock()
+		rc.cache[site] = entry
+		rc.mu.Unlock()
+	}
+
+	path := u.EscapedPath()
+	if path == "" {
+		path = "/"
+	}
+	if u.RawQuery != "" {
+		path += "?" + u.RawQuery
+	}
+	return entry.rules.allowed(userAgent, path), nil
+}
+
+// fetchRobots downloads the robots.txt of site. Following RFC 9309, a missing
+// file allows everything and a server error disallows everything.
+func fetchRobots(ctx context.Context, hc *http.Client, site string) (*robotsRules, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, site+"/robots.txt", nil)
+	if err != nil {
+		return nil, fmt.Errorf("robots.txt: %w", err)
+	}
+
+	resp, err := hc.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("robots.txt: %w", err)
+	}
+	defer resp.Body.Close()
+
+	switch {
+	case resp.StatusCode >= 500:
+		return &robotsRules{disallowAll: true}, nil
+	case resp.StatusCode >= 400:
+		return &robotsRules{}, nil
+	case resp.StatusCode >= 300:
+		return nil, fmt.Errorf("robots.txt: unexpected status %s", resp.Status)
+	}
+
+	// RFC 9309 only requires parsing the first 500 KiB
+	return parseRobots(io.LimitReader(resp.Body, 500<<10))
+}
+
+// robotsRules are the groups of a parsed robots.txt
+type robotsRules struct {
+	groups      []robotsGroup
+	disallowAll bool
+}
+
+type robotsGroup struct {
+	agents []string
+	rules  []robotsRule
+}
+
+type robotsRule struct {
+	allow   bool
+	pattern string
+}
+
+func parseRobots(r io.Reader) (*robotsRules, error) {
+	rules := &robotsRules{}
+	var current *robotsGroup
+	inAgents := false
+
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line, _, _ := strings.Cut(scanner.Text(), "#")
+		key, value, ok := strings.Cut(line, ":")
+		if !ok {
+			continue
+		}
+		key = strings.ToLower(strings.TrimSpace(key))
+		value = strings.TrimSpace(value)
+
+		switch key {
+		case "user-agent":
+			// Consecutive user-agent lines share one group
+			if !inAgents {
+				rules.groups = append(rules.groups, robotsGroup{})
+				current = &rules.groups[len(rules.groups)-1]
+			}
+			current.agents = append(current.agents, strings.ToLower(value))
+			inAgents = true
+		case "allow", "disallow":
+			inAgents = false
+			if current == nil || (key == "disallow" && value == "") {
+				continue
+			}
+			current.rules = append(current.rules, robotsRule{allow: key == "allow", pattern: value})
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("robots.txt: %w", err)
+	}
+	return rules, nil
+}
+
+// allowed reports whether userAgent may fetch path. The longest matching
+// rule wins and allow wins ties.
+func (r *robotsRules) allowed(userAgent, path string) bool {
+	if r.disallowAll {
+		return false
+	}
+
+	group := r.group(userAgent)
+	if group == nil {
+		return true
+	}
+
+	allow, matched := true, -1
+	for _, rule := range group.rules {
+		if !robotsMatch(rule.pattern, path) {
+			continue
+		}
+		n := len(rule.pattern)
+		if n > matched || (n == matched && rule.allow) {
+			allow, matched = rule.allow, n
+		}
+	}
+	return allow
+}
+
+// group picks the group naming the product token of userAgent, falling back to "*"
+func (r *robotsRules) group(userAgent string) *robotsGroup {
+	token := strings.ToLower(userAgent)
+	if i := strings.IndexAny(token, "/ "); i >= 0 {
+		token = token[:i]
+	}
+
+	var fallback *robotsGroup
+	for i := range r.groups {
+		for _, agent := range r.groups[i].agents {
+			switch {
+			case token != "" && agent == token:
+				return &r.groups[i]
+			case agent == "*" && fallback == nil:
+				fallback = &r.groups[i]
+			}
+		}
+	}
+	return fallback
+}
+
+// robotsMatch matches path against a robots.txt pattern with * and $ wildcards
+func robotsMatch(pattern, path string) bool {
+	anchored := strings.HasSuffix(pattern, "$")
+	pattern = strings.TrimSuffix(pattern, "$")
+
+	parts := strings.Split(pattern, "*")
+	if !strings.HasPrefix(path, parts[0]) {
+		return false
+	}
+	rest := path[len(parts[0]):]
+	for _, part := range parts[1:] {
+		i := strings.Index(rest, part)
+		if i < 0 {
+			return false
+		}
+		rest = rest[i+len(part):]
+	}
+	if !anchored {
+		return true
+	}
+	if len(parts) > 1 {
+		// The last literal must end the path, so re-check it against the suffix
+		return strings.HasSuffix(path, parts[len(parts)-1])
+	}
+	return rest == ""
+}
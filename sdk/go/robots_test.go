@@ -0,0 +1,151 @@
+package scrapeapi
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestRobotsMatch(t *testing.T) {
+	tests := []struct {
+		pattern string
+		path    string
+		want    bool
+	}{
+		{"/", "/", true},
+		{"/", "/anything", true},
+		{"/private", "/private", true},
+		{"/private", "/private/page", true},
+		{"/private", "/privately", true},
+		{"/private", "/public", false},
+		{"/private/", "/private", false},
+		{"/*.php", "/index.php", true},
+		{"/*.php", "/dir/index.php?x=1", true},
+		{"/*.php", "/index.html", false},
+		{"/*.php$", "/index.php", true},
+		{"/*.php$", "/index.php?x=1", false},
+		{"/*.php$", "/a.php/b.php", true},
+		{"/*.php$", "/a.php/b", false},
+		{"/page$", "/page", true},
+		{"/page$", "/page/", false},
+		{"/a*b*c", "/a-b-c", true},
+		{"/a*b*c", "/a-c-b", false},
+		{"/a*$", "/a-anything", true},
+		{"/*?sort=", "/list?sort=asc", true},
+		{"*", "/", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.pattern+" "+tt.path, func(t *testing.T) {
+			if got := robotsMatch(tt.pattern, tt.path); got != tt.want {
+				t.Errorf("robotsMatch(%q, %q) = %v, want %v", tt.pattern, tt.path, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRobotsRulesAllowed(t *testing.T) {
+	const robotsTxt = `
+# Comments and unknown lines are ignored
+Sitemap: https://example.com/sitemap.xml
+
+User-agent: *
+Disallow: /private
+Allow: /private/public
+Disallow: /*.pdf$
+Allow: /tie
+Disallow: /tie
+Disallow:
+
+User-agent: GoodBot
+User-agent: OtherBot
+Allow: /
+Disallow: /admin # trailing comment
+
+User-agent: StrictBot
+Disallow: /
+Allow: /open$
+`
+	rules, err := parseRobots(strings.NewReader(robotsTxt))
+	if err != nil {
+		t.Fatalf("parseRobots: %v", err)
+	}
+
+	tests := []struct {
+		name      string
+		userAgent string
+		path      string
+		want      bool
+	}{
+		{"no matching rule", "", "/about", true},
+		{"disallowed prefix", "", "/private/page", false},
+		{"longer allow wins", "", "/private/public/page", true},
+		{"anchored wildcard", "", "/docs/file.pdf", false},
+		{"anchored wildcard with query", "", "/docs/file.pdf?dl=1", true},
+		{"allow wins ties", "", "/tie", true},
+		{"empty disallow is ignored", "", "/", true},
+		{"named group", "GoodBot", "/private/page", true},
+		{"named group disallow", "GoodBot", "/admin/users", false},
+		{"product token of full user agent", "goodbot/2.1 (+https://example.com)", "/private", true},
+		{"consecutive user-agent lines share a group", "OtherBot", "/admin", false},
+		{"unknown agent falls back to *", "NoBot/1.0", "/private", false},
+		{"disallow all", "StrictBot", "/page", false},
+		{"longer allow over disallow all", "StrictBot", "/open", true},
+		{"anchored allow", "StrictBot", "/open/more", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := rules.allowed(tt.userAgent, tt.path); got != tt.want {
+				t.Errorf("allowed(%q, %q) = %v, want %v", tt.userAgent, tt.path, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRobotsRulesNoGroup(t *testing.T) {
+	rules, err := parseRobots(strings.NewReader("User-agent: OnlyBot\nDisallow: /\n"))
+	if err != nil {
+		t.Fatalf("parseRobots: %v", err)
+	}
+	if !rules.allowed("OtherBot", "/page") {
+		t.Error("agent without a group or * fallback should be allowed")
+	}
+	if rules.allowed("OnlyBot", "/page") {
+		t.Error("OnlyBot should be disallowed")
+	}
+}
+
+func TestRobotsAllowedFetch(t *testing.T) {
+	tests := []struct {
+		name   string
+		status int
+		body   string
+		want   bool
+	}{
+		{"rules apply", http.StatusOK, "User-agent: *\nDisallow: /private\n", false},
+		{"missing file allows everything", http.StatusNotFound, "", true},
+		{"server error disallows everything", http.StatusServiceUnavailable, "", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.URL.Path != "/robots.txt" {
+					t.Errorf("fetched %s, want /robots.txt", r.URL.Path)
+				}
+				w.WriteHeader(tt.status)
+				w.Write([]byte(tt.body))
+			}))
+			defer srv.Close()
+
+			c := NewClient("http://127.0.0.1:0")
+			got, err := c.RobotsAllowed(context.Background(), "", srv.URL+"/private/page")
+			if err != nil {
+				t.Fatalf("RobotsAllowed: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("RobotsAllowed = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,455 @@
+package scrapeapi
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/invopop/jsonschema"
+)
+
+// newReflector returns the JSON Schema reflector used by the SDK.
+// Definitions are inlined rather than referenced via $ref/$defs, which the
+// server's schema-to-model conversion handles poorly, and the top-level
+// schema is the struct itself rather than a reference to it.
+func newReflector() *jsonschema.Reflector {
+	return &jsonschema.Reflector{
+		DoNotReference: true,
+		ExpandedStruct: true,
+	}
+}
+
+// SchemaFor generates an OutputSchema from the Go type T.
+// Fields are required unless tagged omitempty, unknown properties are
+// disallowed, and descriptions, enums and examples are read from jsonschema
+// struct tags or from fields. Types that contain themselves, such as a tree
+// node with a list of children, are described in $defs and referenced with
+// $ref; everything else is inlined.
+// It panics if a field spec names a property T does not have.
+//
+//	req.OutputSchema = scrapeapi.SchemaFor[JobListings]()
+func SchemaFor[T any](fields ...*FieldSpec) *jsonschema.Schema {
+	return mustApplyFields(reflectSchema(reflect.TypeFor[T]()), fields)
+}
+
+// SchemaForValue generates an OutputSchema from the dynamic type of v, like SchemaFor
+func SchemaForValue(v interface{}, fields ...*FieldSpec) *jsonschema.Schema {
+	if v == nil {
+		return mustApplyFields(newReflector().Reflect(v), fields)
+	}
+	return mustApplyFields(reflectSchema(reflect.TypeOf(v)), fields)
+}
+
+// reflectSchema generates the schema of t. Inlining a recursive type would
+// never end, so those are reflected with references, and every definition
+// that is not part of a cycle is inlined again afterwards.
+func reflectSchema(t reflect.Type) *jsonschema.Schema {
+	if !isRecursive(t, map[reflect.Type]bool{}) {
+		return newReflector().ReflectFromType(t)
+	}
+
+	ref := (&jsonschema.Reflector{}).ReflectFromType(t)
+	in := &inliner{defs: ref.Definitions, recursive: map[string]bool{}}
+	for name := range in.defs {
+		if in.reaches(name, name, map[string]bool{}) {
+			in.recursive[name] = true
+		}
+	}
+
+	root := ref
+	if name, ok := strings.CutPrefix(ref.Ref, defsPrefix); ok && in.defs[name] != nil {
+		root = in.defs[name]
+	}
+	schema := in.clone(root)
+	schema.Version = ref.Version
+	schema.Definitions = jsonschema.Definitions{}
+	for name := range in.recursive {
+		schema.Definitions[name] = in.clone(in.defs[name])
+	}
+	return schema
+}
+
+// isRecursive reports whether a struct type reachable from t contains
+// itself; path holds the struct types on the way to t
+func isRecursive(t reflect.Type, path map[reflect.Type]bool) bool {
+	t = schemaType(t)
+	switch t.Kind() {
+	case reflect.Slice, reflect.Array, reflect.Map:
+		return isRecursive(t.Elem(), path)
+	case reflect.Struct:
+	default:
+		return false
+	}
+	if path[t] {
+		return true
+	}
+	path[t] = true
+	defer delete(path, t)
+
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if (f.IsExported() || f.Anonymous) && isRecursive(f.Type, path) {
+			return true
+		}
+	}
+	return false
+}
+
+// defsPrefix starts the references the reflector generates
+const defsPrefix = "#/$defs/"
+
+// inliner replaces references to the definitions that are not part of a
+// cycle with copies of them
+type inliner struct {
+	defs      jsonschema.Definitions
+	recursive map[string]bool
+}
+
+// reaches reports whether definition from refers to target, directly or
+// through other definitions
+func (in *inliner) reaches(from, target string, seen map[string]bool) bool {
+	if seen[from] {
+		return false
+	}
+	seen[from] = true
+	for ref := range schemaRefs(in.defs[from]) {
+		if ref == target || in.reaches(ref, target, seen) {
+			return true
+		}
+	}
+	return false
+}
+
+// schemaRefs returns the names of the definitions s refers to
+func schemaRefs(s *jsonschema.Schema) map[string]bool {
+	refs := map[string]bool{}
+	var walk func(s *jsonschema.Schema)
+	walk = func(s *jsonschema.Schema) {
+		if s == nil {
+			return
+		}
+		if name, ok := strings.CutPrefix(s.Ref, defsPrefix); ok {
+			refs[name] = true
+		}
+		for _, child := range subschemas(s) {
+			walk(child)
+		}
+	}
+	walk(s)
+	return refs
+}
+
+// subschemas returns the schemas nested directly in s
+func subschemas(s *jsonschema.Schema) []*jsonschema.Schema {
+	children := []*jsonschema.Schema{s.Items, s.AdditionalProperties, s.Not, s.Contains, s.PropertyNames}
+	children = append(children, s.AllOf...)
+	children = append(children, s.AnyOf...)
+	children = append(children, s.OneOf...)
+	children = append(children, s.PrefixItems...)
+	if s.Properties != nil {
+		for p := s.Properties.Oldest(); p != nil; p = p.Next() {
+			children = append(children, p.Value)
+		}
+	}
+	for _, child := range s.PatternProperties {
+		children = append(children, child)
+	}
+	return children
+}
+
+// clone deep-copies s, replacing references to definitions outside a cycle
+// with copies of them, so field specs applied later affect one place only
+func (in *inliner) clone(s *jsonschema.Schema) *jsonschema.Schema {
+	if s == nil {
+		return nil
+	}
+	if name, ok := strings.CutPrefix(s.Ref, defsPrefix); ok && !in.recursive[name] && in.defs[name] != nil {
+		def := in.clone(in.defs[name])
+		if s.Title != "" {
+			def.Title = s.Title
+		}
+		if s.Description != "" {
+			def.Description = s.Description
+		}
+		return def
+	}
+
+	c := *s
+	c.Items = in.clone(s.Items)
+	c.AdditionalProperties = in.clone(s.AdditionalProperties)
+	c.Not = in.clone(s.Not)
+	c.Contains = in.clone(s.Contains)
+	c.PropertyNames = in.clone(s.PropertyNames)
+	c.AllOf = in.cloneAll(s.AllOf)
+	c.AnyOf = in.cloneAll(s.AnyOf)
+	c.OneOf = in.cloneAll(s.OneOf)
+	c.PrefixItems = in.cloneAll(s.PrefixItems)
+	if s.Properties != nil {
+		c.Properties = jsonschema.NewProperties()
+		for p := s.Properties.Oldest(); p != nil; p = p.Next() {
+			c.Properties.Set(p.Key, in.clone(p.Value))
+		}
+	}
+	if s.PatternProperties != nil {
+		c.PatternProperties = make(map[string]*jsonschema.Schema, len(s.PatternProperties))
+		for k, child := range s.PatternProperties {
+			c.PatternProperties[k] = in.clone(child)
+		}
+	}
+	return &c
+}
+
+func (in *inliner) cloneAll(schemas []*jsonschema.Schema) []*jsonschema.Schema {
+	if schemas == nil {
+		return nil
+	}
+	out := make([]*jsonschema.Schema, len(schemas))
+	for i, s := range schemas {
+		out[i] = in.clone(s)
+	}
+	return out
+}
+
+func mustApplyFields(schema *jsonschema.Schema, fields []*FieldSpec) *jsonschema.Schema {
+	if err := ApplyFields(schema, fields...); err != nil {
+		panic("scrapeapi: " + err.Error())
+	}
+	return schema
+}
+
+// SchemaTagProblem is a jsonschema struct tag that SchemaFor would ignore or misread
+type SchemaTagProblem struct {
+	// Field is the Go field, qualified by its struct type, e.g. "ParsedJob.Age"
+	Field   string
+	Message string
+}
+
+// SchemaTagError lists every problem found by CheckSchemaTags
+type SchemaTagError struct {
+	Problems []SchemaTagProblem
+}
+
+func (e *SchemaTagError) Error() string {
+	msgs := make([]string, len(e.Problems))
+	for i, p := range e.Problems {
+		msgs[i] = p.Field + ": " + p.Message
+	}
+	return "invalid jsonschema tags: " + strings.Join(msgs, "; ")
+}
+
+// CheckSchemaTags reports jsonschema struct tags of T and the types it nests
+// that the schema generator would silently drop: malformed tag syntax, bare
+// words instead of key=value pairs, unknown or whitespace-padded keys, and
+// keys that do not apply to the field's type. Call it from a test or at
+// startup, since a dropped description never reaches the LLM.
+func CheckSchemaTags[T any]() error {
+	c := &tagChecker{seen: map[reflect.Type]bool{}}
+	c.checkType(reflect.TypeFor[T]())
+	if len(c.problems) > 0 {
+		return &SchemaTagError{Problems: c.problems}
+	}
+	return nil
+}
+
+// Keys understood by the schema generator, by JSON Schema type of the field
+var (
+	genericTagKeys = []string{"title", "description", "type", "anchor", "oneof_required", "anyof_required", "oneof_ref", "oneof_type", "anyof_ref", "anyof_type"}
+	kindTagKeys    = map[string][]string{
+		"string":  {"minLength", "maxLength", "pattern", "format", "readOnly", "writeOnly", "default", "example", "enum"},
+		"number":  {"multipleOf", "minimum", "maximum", "exclusiveMaximum", "exclusiveMinimum", "default", "example", "enum"},
+		"boolean": {"default"},
+		"array":   {"minItems", "maxItems", "uniqueItems", "default", "format", "pattern"},
+	}
+	countTagKeys  = []string{"minLength", "maxLength", "minItems", "maxItems"}
+	numberTagKeys = []string{"multipleOf", "minimum", "maximum", "exclusiveMaximum", "exclusiveMinimum"}
+)
+
+type tagChecker struct {
+	seen     map[reflect.Type]bool
+	problems []SchemaTagProblem
+}
+
+func (c *tagChecker) checkType(t reflect.Type) {
+	t = schemaType(t)
+	switch t.Kind() {
+	case reflect.Slice, reflect.Array, reflect.Map:
+		c.checkType(t.Elem())
+		return
+	case reflect.Struct:
+	default:
+		return
+	}
+	if c.seen[t] {
+		return
+	}
+	c.seen[t] = true
+
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if !f.IsExported() && !f.Anonymous {
+			continue
+		}
+		name := t.Name() + "." + f.Name
+		if err := checkTagSyntax(f.Tag); err != nil {
+			c.addf(name, "malformed struct tag: %v", err)
+		} else if tag, ok := f.Tag.Lookup("jsonschema"); ok {
+			c.checkTag(name, tag, f.Type)
+		}
+		c.checkType(f.Type)
+	}
+}
+
+func (c *tagChecker) checkTag(field, tag string, t reflect.Type) {
+	kind := schemaKind(t)
+	var elemKind string
+	if kind == "array" {
+		elemKind = schemaKind(schemaType(t).Elem())
+	}
+
+	for i, part := range splitTagValues(tag) {
+		key, value, hasValue := strings.Cut(part, "=")
+		if key != strings.TrimSpace(key) {
+			c.addf(field, "key %q has surrounding whitespace and is ignored", key)
+			continue
+		}
+		if !hasValue {
+			switch {
+			case part == "-" && i == 0, part == "required", part == "nullable", part == "":
+			default:
+				c.addf(field, "%q is not a key=value pair and is ignored", part)
+			}
+			continue
+		}
+
+		switch {
+		case slices.Contains(genericTagKeys, key):
+		case slices.Contains(kindTagKeys[kind], key), kind == "array" && slices.Contains(kindTagKeys[elemKind], key):
+			if slices.Contains(countTagKeys, key) {
+				if _, err := strconv.ParseUint(value, 10, 64); err != nil {
+					c.addf(field, "%s must be a non-negative integer, got %q", key, value)
+				}
+			}
+			if slices.Contains(numberTagKeys, key) {
+				if _, err := strconv.ParseFloat(value, 64); err != nil {
+					c.addf(field, "%s must be a number, got %q", key, value)
+				}
+			}
+		case isKnownTagKey(key):
+			c.addf(field, "%s does not apply to a field of type %s and is ignored", key, t)
+		default:
+			c.addf(field, "unknown key %q is ignored", key)
+		}
+	}
+}
+
+func (c *tagChecker) addf(field, format string, args ...interface{}) {
+	c.problems = append(c.problems, SchemaTagProblem{Field: field, Message: fmt.Sprintf(format, args...)})
+}
+
+func isKnownTagKey(key string) bool {
+	for _, keys := range kindTagKeys {
+		if slices.Contains(keys, key) {
+			return true
+		}
+	}
+	return false
+}
+
+// schemaType is the type the schema generator describes t as: pointers are
+// dereferenced and types with a JSONSchemaAlias, like FieldValue, are replaced by the alias
+func schemaType(t reflect.Type) reflect.Type {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	if alias, ok := reflect.New(t).Elem().Interface().(interface{ JSONSchemaAlias() any }); ok {
+		return schemaType(reflect.TypeOf(alias.JSONSchemaAlias()))
+	}
+	return t
+}
+
+// schemaKind is the JSON Schema type generated for t, or "" for objects and unknown types
+func schemaKind(t reflect.Type) string {
+	t = schemaType(t)
+	switch t {
+	case reflect.TypeFor[time.Time]():
+		return "string"
+	case reflect.TypeFor[json.Number]():
+		return "number"
+	}
+	switch t.Kind() {
+	case reflect.String:
+		return "string"
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		return "number"
+	case reflect.Bool:
+		return "boolean"
+	case reflect.Slice, reflect.Array:
+		if t.Elem().Kind() == reflect.Uint8 {
+			// Byte slices are encoded as base64 strings
+			return "string"
+		}
+		return "array"
+	}
+	return ""
+}
+
+// splitTagValues splits a jsonschema tag on commas not escaped with a backslash
+func splitTagValues(tag string) []string {
+	var parts []string
+	var cur strings.Builder
+	for i := 0; i < len(tag); i++ {
+		switch {
+		case tag[i] == '\\' && i+1 < len(tag) && tag[i+1] == ',':
+			cur.WriteByte(',')
+			i++
+		case tag[i] == ',':
+			parts = append(parts, cur.String())
+			cur.Reset()
+		default:
+			cur.WriteByte(tag[i])
+		}
+	}
+	return append(parts, cur.String())
+}
+
+// checkTagSyntax verifies that tag follows the key:"value" convention that
+// reflect.StructTag.Get relies on; Get stops at the first malformed pair
+func checkTagSyntax(tag reflect.StructTag) error {
+	s := string(tag)
+	for {
+		s = strings.TrimLeft(s, " ")
+		if s == "" {
+			return nil
+		}
+		i := 0
+		for i < len(s) && s[i] > ' ' && s[i] != ':' && s[i] != '"' && s[i] != 0x7f {
+			i++
+		}
+		if i == 0 || i+1 >= len(s) || s[i] != ':' || s[i+1] != '"' {
+			return fmt.Errorf("bad syntax near %q", s)
+		}
+		key := s[:i]
+		s = s[i+1:]
+
+		i = 1
+		for i < len(s) && s[i] != '"' {
+			if s[i] == '\\' {
+				i++
+			}
+			i++
+		}
+		if i >= len(s) {
+			return fmt.Errorf("unterminated value for key %q", key)
+		}
+		if _, err := strconv.Unquote(s[:i+1]); err != nil {
+			return fmt.Errorf("bad quoted value for key %q", key)
+		}
+		s = s[i+1:]
+	}
+}
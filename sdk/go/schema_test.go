@@ -0,0 +1,176 @@
+package scrapeapi
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+type treeNode struct {
+	Name     string     `json:"name"`
+	Children []treeNode `json:"children,omitempty"`
+	Home     place      `json:"home"`
+	Work     place      `json:"work"`
+}
+
+type place struct {
+	City string `json:"city"`
+}
+
+type org struct {
+	Name  string `json:"name"`
+	Teams []team `json:"teams"`
+}
+
+type team struct {
+	Lead *person `json:"lead"`
+}
+
+type person struct {
+	Name string `json:"name"`
+	Org  *org   `json:"org,omitempty"`
+}
+
+type flatListing struct {
+	Title string `json:"title"`
+	Home  place  `json:"home"`
+}
+
+// schemaJSON generically decodes a generated schema
+func schemaJSON(t *testing.T, schema interface{}) map[string]interface{} {
+	t.Helper()
+	raw, err := json.Marshal(schema)
+	if err != nil {
+		t.Fatalf("marshal schema: %v", err)
+	}
+	var m map[string]interface{}
+	if err := json.Unmarshal(raw, &m); err != nil {
+		t.Fatalf("decode schema: %v", err)
+	}
+	return m
+}
+
+// schemaAt follows a slash-separated path of keys through a decoded schema
+func schemaAt(m map[string]interface{}, path string) interface{} {
+	var node interface{} = m
+	for _, key := range strings.Split(path, "/") {
+		obj, _ := node.(map[string]interface{})
+		node = obj[key]
+	}
+	return node
+}
+
+func TestSchemaForRecursiveTypes(t *testing.T) {
+	tests := []struct {
+		name     string
+		schema   interface{}
+		wantDefs []string
+		want     map[string]interface{}
+	}{
+		{
+			name:   "not recursive",
+			schema: SchemaFor[flatListing](),
+			want: map[string]interface{}{
+				"properties/home/properties/city/type": "string",
+				"$ref":                                 nil,
+			},
+		},
+		{
+			name:     "self reference",
+			schema:   SchemaFor[treeNode](),
+			wantDefs: []string{"treeNode"},
+			want: map[string]interface{}{
+				"type":                                                "object",
+				"properties/children/items/$ref":                      "#/$defs/treeNode",
+				"properties/home/properties/city/type":                "string",
+				"properties/home/$ref":                                nil,
+				"$defs/treeNode/properties/children/items/$ref":       "#/$defs/treeNode",
+				"$defs/treeNode/properties/work/properties/city/type": "string",
+			},
+		},
+		{
+			name:     "mutual reference through pointers",
+			schema:   SchemaFor[org](),
+			wantDefs: []string{"org", "person", "team"},
+			want: map[string]interface{}{
+				"properties/teams/items/$ref":           "#/$defs/team",
+				"$defs/team/properties/lead/$ref":       "#/$defs/person",
+				"$defs/person/properties/org/$ref":      "#/$defs/org",
+				"$defs/person/properties/name/type":     "string",
+				"$defs/org/properties/teams/items/$ref": "#/$defs/team",
+			},
+		},
+		{
+			name:     "value",
+			schema:   SchemaForValue([]treeNode{}),
+			wantDefs: []string{"treeNode"},
+			want: map[string]interface{}{
+				"type":       "array",
+				"items/$ref": "#/$defs/treeNode",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := schemaJSON(t, tt.schema)
+
+			defs, _ := m["$defs"].(map[string]interface{})
+			var names []string
+			for name := range defs {
+				names = append(names, name)
+			}
+			if len(names) != len(tt.wantDefs) {
+				t.Errorf("$defs = %v, want %v", names, tt.wantDefs)
+			}
+			for _, name := range tt.wantDefs {
+				if defs[name] == nil {
+					t.Errorf("$defs has no %s", name)
+				}
+			}
+			for path, want := range tt.want {
+				if got := schemaAt(m, path); !reflect.DeepEqual(got, want) {
+					t.Errorf("%s = %v, want %v", path, got, want)
+				}
+			}
+		})
+	}
+}
+
+func TestSchemaForRecursiveFields(t *testing.T) {
+	schema := SchemaFor[treeNode](
+		Field("home.city").Describe("Where the node lives"),
+		Field("children.name").Describe("Name of a child"),
+	)
+	m := schemaJSON(t, schema)
+
+	if got := schemaAt(m, "properties/home/properties/city/description"); got != "Where the node lives" {
+		t.Errorf("home.city description = %v", got)
+	}
+	// Inlined copies are independent, so the spec only reaches home
+	if got := schemaAt(m, "properties/work/properties/city/description"); got != nil {
+		t.Errorf("work.city description = %v, want none", got)
+	}
+	if got := schemaAt(m, "$defs/treeNode/properties/name/description"); got != "Name of a child" {
+		t.Errorf("children.name description = %v", got)
+	}
+}
+
+func TestSchemaForRecursiveValidates(t *testing.T) {
+	var result interface{}
+	if err := json.Unmarshal([]byte(`{
+		"name": "root", "home": {"city": "Berlin"}, "work": {"city": "Berlin"},
+		"children": [{"name": "leaf", "home": {"city": 1}, "work": {"city": "Paris"}}]
+	}`), &result); err != nil {
+		t.Fatal(err)
+	}
+
+	err := ValidateResult(&ScrapeResponse{RequestID: "req_1", Result: result}, SchemaFor[treeNode]())
+	sve, ok := err.(*SchemaValidationError)
+	if !ok {
+		t.Fatalf("ValidateResult = %v, want a *SchemaValidationError", err)
+	}
+	if len(sve.Problems) != 1 || sve.Problems[0].Path != "children[0].home.city" {
+		t.Errorf("problems = %v, want one at children[0].home.city", sve.Problems)
+	}
+}
@@ -0,0 +1,207 @@
+package scrapeapi
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/invopop/jsonschema"
+)
+
+// SchemaFromJSON infers an OutputSchema from a sample of the desired output,
+// for authoring schemas without Go types. Properties keep the sample's order
+// and are required when present in every sampled object; list items are
+// merged across all elements, null values make a field nullable, and
+// strings that look like timestamps or URLs get a date-time or uri format.
+// Unknown properties are disallowed, as with SchemaFor.
+//
+//	schema, err := scrapeapi.SchemaFromJSON([]byte(`{"jobs": [{"title": "Go developer", "salary": 120000}]}`))
+func SchemaFromJSON(sample []byte) (*jsonschema.Schema, error) {
+	dec := json.NewDecoder(bytes.NewReader(sample))
+	dec.UseNumber()
+	v, err := decodeOrdered(dec)
+	if err != nil {
+		return nil, fmt.Errorf("sample JSON: %w", err)
+	}
+	if _, err := dec.Token(); err != io.EOF {
+		return nil, fmt.Errorf("sample JSON: unexpected data after the top-level value")
+	}
+
+	schema := inferSchema(v)
+	schema.Version = jsonschema.Version
+	return schema, nil
+}
+
+// orderedObject is a decoded JSON object that remembers its key order
+type orderedObject struct {
+	keys   []string
+	values map[string]interface{}
+}
+
+// decodeOrdered decodes the next JSON value, keeping object key order
+func decodeOrdered(dec *json.Decoder) (interface{}, error) {
+	tok, err := dec.Token()
+	if err != nil {
+		return nil, err
+	}
+	switch t := tok.(type) {
+	case json.Delim:
+		switch t {
+		case '{':
+			obj := &orderedObject{values: map[string]interface{}{}}
+			for dec.More() {
+				keyTok, err := dec.Token()
+				if err != nil {
+					return nil, err
+				}
+				key := keyTok.(string)
+				v, err := decodeOrdered(dec)
+				if err != nil {
+					return nil, err
+				}
+				if _, dup := obj.values[key]; !dup {
+					obj.keys = append(obj.keys, key)
+				}
+				obj.values[key] = v
+			}
+			_, err := dec.Token()
+			return obj, err
+		case '[':
+			arr := []interface{}{}
+			for dec.More() {
+				v, err := decodeOrdered(dec)
+				if err != nil {
+					return nil, err
+				}
+				arr = append(arr, v)
+			}
+			_, err := dec.Token()
+			return arr, err
+		}
+	}
+	return tok, nil
+}
+
+func inferSchema(v interface{}) *jsonschema.Schema {
+	switch val := v.(type) {
+	case *orderedObject:
+		s := &jsonschema.Schema{
+			Type:                 "object",
+			Properties:           jsonschema.NewProperties(),
+			AdditionalProperties: jsonschema.FalseSchema,
+			Required:             []string{},
+		}
+		for _, k := range val.keys {
+			s.Properties.Set(k, inferSchema(val.values[k]))
+			s.Required = append(s.Required, k)
+		}
+		return s
+	case []interface{}:
+		s := &jsonschema.Schema{Type: "array"}
+		for _, item := range val {
+			s.Items = mergeSchemas(s.Items, inferSchema(item))
+		}
+		return s
+	case json.Number:
+		if _, err := val.Int64(); err == nil {
+			return &jsonschema.Schema{Type: "integer"}
+		}
+		return &jsonschema.Schema{Type: "number"}
+	case string:
+		return &jsonschema.Schema{Type: "string", Format: stringFormat(val)}
+	case bool:
+		return &jsonschema.Schema{Type: "boolean"}
+	}
+	return &jsonschema.Schema{Type: "null"}
+}
+
+// stringFormat guesses the format of a sample string
+func stringFormat(s string) string {
+	if _, err := time.Parse(time.RFC3339, s); err == nil {
+		return "date-time"
+	}
+	if _, err := time.Parse(time.DateOnly, s); err == nil {
+		return "date"
+	}
+	if strings.HasPrefix(s, "http://") || strings.HasPrefix(s, "https://") {
+		return "uri"
+	}
+	return ""
+}
+
+// mergeSchemas combines the schemas inferred from two samples of one value
+func mergeSchemas(a, b *jsonschema.Schema) *jsonschema.Schema {
+	switch {
+	case a == nil:
+		return b
+	case b == nil:
+		return a
+	case a.Type == "null" && b.Type == "null":
+		return a
+	case b.Type == "null":
+		return nullable(a)
+	case a.Type == "null":
+		return nullable(b)
+	}
+
+	// An already nullable schema merges its non-null branch
+	if inner, ok := nonNullBranch(a); ok {
+		return nullable(mergeSchemas(inner, b))
+	}
+	if inner, ok := nonNullBranch(b); ok {
+		return nullable(mergeSchemas(a, inner))
+	}
+
+	switch {
+	case a.Type == b.Type && a.Type == "object":
+		merged := &jsonschema.Schema{
+			Type:                 "object",
+			Properties:           jsonschema.NewProperties(),
+			AdditionalProperties: jsonschema.FalseSchema,
+			Required:             []string{},
+		}
+		for pair := a.Properties.Oldest(); pair != nil; pair = pair.Next() {
+			other, _ := b.Properties.Get(pair.Key)
+			merged.Properties.Set(pair.Key, mergeSchemas(pair.Value, other))
+			if slices.Contains(b.Required, pair.Key) && slices.Contains(a.Required, pair.Key) {
+				merged.Required = append(merged.Required, pair.Key)
+			}
+		}
+		for pair := b.Properties.Oldest(); pair != nil; pair = pair.Next() {
+			if _, ok := a.Properties.Get(pair.Key); !ok {
+				merged.Properties.Set(pair.Key, pair.Value)
+			}
+		}
+		return merged
+	case a.Type == b.Type && a.Type == "array":
+		return &jsonschema.Schema{Type: "array", Items: mergeSchemas(a.Items, b.Items)}
+	case a.Type == b.Type && a.Type != "":
+		merged := *a
+		if a.Format != b.Format {
+			merged.Format = ""
+		}
+		return &merged
+	case (a.Type == "integer" && b.Type == "number") || (a.Type == "number" && b.Type == "integer"):
+		return &jsonschema.Schema{Type: "number"}
+	}
+	return &jsonschema.Schema{AnyOf: []*jsonschema.Schema{a, b}}
+}
+
+func nullable(s *jsonschema.Schema) *jsonschema.Schema {
+	if _, ok := nonNullBranch(s); ok {
+		return s
+	}
+	return &jsonschema.Schema{AnyOf: []*jsonschema.Schema{s, {Type: "null"}}}
+}
+
+// nonNullBranch returns x for a schema built by nullable(x)
+func nonNullBranch(s *jsonschema.Schema) (*jsonschema.Schema, bool) {
+	if len(s.AnyOf) == 2 && s.AnyOf[1].Type == "null" {
+		return s.AnyOf[0], true
+	}
+	return nil, false
+}
@@ -0,0 +1,292 @@
+package scrapeapi
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// SchemaProblemKind classifies a mismatch between a result and its schema
+type SchemaProblemKind string
+
+// Kinds of schema problems
+const (
+	// ProblemMissing is a required property that is absent
+	ProblemMissing SchemaProblemKind = "missing"
+	// ProblemType is a value of the wrong JSON type
+	ProblemType SchemaProblemKind = "type"
+	// ProblemEnum is a value outside the allowed enum
+	ProblemEnum SchemaProblemKind = "enum"
+	// ProblemUnexpected is a property the schema does not allow
+	ProblemUnexpected SchemaProblemKind = "unexpected"
+	// ProblemConstraint is a value violating another keyword, e.g. minItems
+	ProblemConstraint SchemaProblemKind = "constraint"
+)
+
+// SchemaProblem is one place where a result does not match its schema
+type SchemaProblem struct {
+	// Path locates the value in the result, e.g. "jobs[3].salary"; empty for the root
+	Path    string
+	Kind    SchemaProblemKind
+	Message string
+}
+
+func (p SchemaProblem) String() string {
+	path := p.Path
+	if path == "" {
+		path = "(root)"
+	}
+	return path + ": " + p.Message
+}
+
+// SchemaValidationError lists every place a job result deviates from the OutputSchema
+type SchemaValidationError struct {
+	RequestID string
+	Problems  []SchemaProblem
+}
+
+func (e *SchemaValidationError) Error() string {
+	msgs := make([]string, len(e.Problems))
+	for i, p := range e.Problems {
+		msgs[i] = p.String()
+	}
+	return fmt.Sprintf("result of %s does not match output schema: %s", e.RequestID, strings.Join(msgs, "; "))
+}
+
+// WithResultValidation makes ScrapeAndWait check the completed result against
+// the request's OutputSchema, returning a *SchemaValidationError alongside the
+// response when they do not match. Requests without a JSON Schema are not checked.
+func WithResultValidation() WaitOption {
+	return func(cfg *waitConfig) {
+		cfg.validateResult = true
+	}
+}
+
+// ValidateResult checks the result of resp against schema, which may be any
+// value that marshals to a JSON Schema object
+func ValidateResult(resp *ScrapeResponse, schema interface{}) error {
+	raw, err := json.Marshal(schema)
+	if err != nil {
+		return fmt.Errorf("marshal schema: %w", err)
+	}
+	var root map[string]interface{}
+	if err := json.Unmarshal(raw, &root); err != nil {
+		// Example strings and other non-object schemas cannot be checked
+		return nil
+	}
+
+	data := resultData(resp.Result)
+	// Normalize typed values to the generic JSON representation
+	if raw, err := json.Marshal(data); err == nil {
+		_ = json.Unmarshal(raw, &data)
+	}
+
+	sv := &schemaValidator{root: root}
+	sv.validate("", data, root)
+	if len(sv.problems) == 0 {
+		return nil
+	}
+	return &SchemaValidationError{RequestID: resp.RequestID, Problems: sv.problems}
+}
+
+// schemaValidator checks values against the subset of JSON Schema that
+// output schemas use: type, properties, required, additionalProperties,
+// items, enum, const, anyOf/oneOf, min/max items and local $ref.
+type schemaValidator struct {
+	root     map[string]interface{}
+	problems []SchemaProblem
+}
+
+func (sv *schemaValidator) addf(path string, kind SchemaProblemKind, format string, args ...interface{}) {
+	sv.problems = append(sv.problems, SchemaProblem{Path: path, Kind: kind, Message: fmt.Sprintf(format, args...)})
+}
+
+func (sv *schemaValidator) validate(path string, v interface{}, schema map[string]interface{}) {
+	schema = sv.resolve(schema)
+
+	for _, key := range []string{"anyOf", "oneOf"} {
+		if branches, ok := schema[key].([]interface{}); ok {
+			if !sv.matchesAny(v, branches) {
+				sv.addf(path, ProblemType, "does not match any allowed schema")
+			}
+		}
+	}
+
+	if types := schemaTypes(schema); len(types) > 0 && !typeMatches(v, types) {
+		sv.addf(path, ProblemType, "expected %s, got %s", strings.Join(types, " or "), jsonType(v))
+		return
+	}
+
+	if enum, ok := schema["enum"].([]interface{}); ok && !containsValue(enum, v) {
+		sv.addf(path, ProblemEnum, "value %v is not one of %v", v, enum)
+	}
+	if c, ok := schema["const"]; ok && !reflect.DeepEqual(c, v) {
+		sv.addf(path, ProblemEnum, "value %v is not %v", v, c)
+	}
+
+	switch val := v.(type) {
+	case map[string]interface{}:
+		sv.validateObject(path, val, schema)
+	case []interface{}:
+		sv.validateArray(path, val, schema)
+	}
+}
+
+func (sv *schemaValidator) validateObject(path string, obj map[string]interface{}, schema map[string]interface{}) {
+	props, _ := schema["properties"].(map[string]interface{})
+
+	if required, ok := schema["required"].([]interface{}); ok {
+		for _, r := range required {
+			name, _ := r.(string)
+			if _, ok := obj[name]; !ok {
+				sv.addf(joinPath(path, name), ProblemMissing, "required field is missing")
+			}
+		}
+	}
+
+	keys := make([]string, 0, len(obj))
+	for k := range obj {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	for _, k := range keys {
+		if propSchema, ok := props[k].(map[string]interface{}); ok {
+			sv.validate(joinPath(path, k), obj[k], propSchema)
+			continue
+		}
+		switch extra := schema["additionalProperties"].(type) {
+		case bool:
+			if !extra {
+				sv.addf(joinPath(path, k), ProblemUnexpected, "field is not allowed by the schema")
+			}
+		case map[string]interface{}:
+			sv.validate(joinPath(path, k), obj[k], extra)
+		}
+	}
+}
+
+func (sv *schemaValidator) validateArray(path string, arr []interface{}, schema map[string]interface{}) {
+	if n, ok := schema["minItems"].(float64); ok && float64(len(arr)) < n {
+		sv.addf(path, ProblemConstraint, "expected at least %g items, got %d", n, len(arr))
+	}
+	if n, ok := schema["maxItems"].(float64); ok && float64(len(arr)) > n {
+		sv.addf(path, ProblemConstraint, "expected at most %g items, got %d", n, len(arr))
+	}
+
+	items, ok := schema["items"].(map[string]interface{})
+	if !ok {
+		return
+	}
+	for i, item := range arr {
+		sv.validate(fmt.Sprintf("%s[%d]", path, i), item, items)
+	}
+}
+
+// matchesAny reports whether v is valid against at least one of branches
+func (sv *schemaValidator) matchesAny(v interface{}, branches []interface{}) bool {
+	for _, b := range branches {
+		branch, ok := b.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		probe := &schemaValidator{root: sv.root}
+		probe.validate("", v, branch)
+		if len(probe.problems) == 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// resolve follows local "#/..." references, giving up on cycles and remote refs
+func (sv *schemaValidator) resolve(schema map[string]interface{}) map[string]interface{} {
+	for range 32 {
+		ref, ok := schema["$ref"].(string)
+		if !ok || !strings.HasPrefix(ref, "#") {
+			return schema
+		}
+		var node interface{} = sv.root
+		for _, part := range strings.Split(strings.TrimPrefix(ref, "#"), "/") {
+			if part == "" {
+				continue
+			}
+			m, _ := node.(map[string]interface{})
+			node = m[part]
+		}
+		next, ok := node.(map[string]interface{})
+		if !ok {
+			return schema
+		}
+		schema = next
+	}
+	return schema
+}
+
+// schemaTypes returns the allowed JSON types of schema
+func schemaTypes(schema map[string]interface{}) []string {
+	switch t := schema["type"].(type) {
+	case string:
+		return []string{t}
+	case []interface{}:
+		types := make([]string, 0, len(t))
+		for _, x := range t {
+			if s, ok := x.(string); ok {
+				types = append(types, s)
+			}
+		}
+		return types
+	}
+	return nil
+}
+
+func typeMatches(v interface{}, types []string) bool {
+	actual := jsonType(v)
+	for _, t := range types {
+		if t == actual || (t == "number" && actual == "integer") {
+			return true
+		}
+	}
+	return false
+}
+
+// jsonType names the JSON type of a generically decoded value
+func jsonType(v interface{}) string {
+	switch val := v.(type) {
+	case nil:
+		return "null"
+	case bool:
+		return "boolean"
+	case float64:
+		if val == math.Trunc(val) {
+			return "integer"
+		}
+		return "number"
+	case string:
+		return "string"
+	case []interface{}:
+		return "array"
+	case map[string]interface{}:
+		return "object"
+	}
+	return fmt.Sprintf("%T", v)
+}
+
+func containsValue(values []interface{}, v interface{}) bool {
+	for _, x := range values {
+		if reflect.DeepEqual(x, v) {
+			return true
+		}
+	}
+	return false
+}
+
+func joinPath(path, field string) string {
+	if path == "" {
+		return field
+	}
+	return path + "." + field
+}
@@ -0,0 +1,234 @@
+package scrapeapi
+
+import (
+	"encoding/json"
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestValidateResult(t *testing.T) {
+	const jobSchema = `{
+		"type": "object",
+		"properties": {
+			"title":  {"type": "string"},
+			"salary": {"type": ["number", "null"]},
+			"level":  {"enum": ["junior", "senior"]}
+		},
+		"required": ["title"],
+		"additionalProperties": false
+	}`
+
+	tests := []struct {
+		name   string
+		schema string
+		result string
+		want   []SchemaProblem
+	}{
+		{
+			name:   "valid",
+			schema: jobSchema,
+			result: `{"title": "Go developer", "salary": 5000.5, "level": "senior"}`,
+		},
+		{
+			name:   "missing required field",
+			schema: jobSchema,
+			result: `{"salary": 5000}`,
+			want:   []SchemaProblem{{Path: "title", Kind: ProblemMissing}},
+		},
+		{
+			name:   "wrong type",
+			schema: jobSchema,
+			result: `{"title": 42}`,
+			want:   []SchemaProblem{{Path: "title", Kind: ProblemType, Message: "expected string, got integer"}},
+		},
+		{
+			name:   "nullable type",
+			schema: jobSchema,
+			result: `{"title": "Go developer", "salary": null}`,
+		},
+		{
+			name:   "enum",
+			schema: jobSchema,
+			result: `{"title": "Go developer", "level": "lead"}`,
+			want:   []SchemaProblem{{Path: "level", Kind: ProblemEnum}},
+		},
+		{
+			name:   "unexpected field",
+			schema: jobSchema,
+			result: `{"title": "Go developer", "remote": true}`,
+			want:   []SchemaProblem{{Path: "remote", Kind: ProblemUnexpected}},
+		},
+		{
+			name:   "problems are reported in field order",
+			schema: jobSchema,
+			result: `{"title": 1, "b": 1, "a": 1}`,
+			want: []SchemaProblem{
+				{Path: "a", Kind: ProblemUnexpected},
+				{Path: "b", Kind: ProblemUnexpected},
+				{Path: "title", Kind: ProblemType},
+			},
+		},
+		{
+			name:   "wrong root type stops at the root",
+			schema: jobSchema,
+			result: `["Go developer"]`,
+			want:   []SchemaProblem{{Path: "", Kind: ProblemType, Message: "expected object, got array"}},
+		},
+		{
+			name:   "integral number is an integer",
+			schema: `{"type": "integer"}`,
+			result: `3.0`,
+		},
+		{
+			name:   "fraction is not an integer",
+			schema: `{"type": "integer"}`,
+			result: `3.5`,
+			want:   []SchemaProblem{{Path: "", Kind: ProblemType}},
+		},
+		{
+			name:   "integer is a number",
+			schema: `{"type": "number"}`,
+			result: `3`,
+		},
+		{
+			name:   "const",
+			schema: `{"const": "v1"}`,
+			result: `"v2"`,
+			want:   []SchemaProblem{{Path: "", Kind: ProblemEnum}},
+		},
+		{
+			name:   "additionalProperties schema",
+			schema: `{"type": "object", "additionalProperties": {"type": "integer"}}`,
+			result: `{"a": 1, "b": "two"}`,
+			want:   []SchemaProblem{{Path: "b", Kind: ProblemType}},
+		},
+		{
+			name:   "array items and bounds",
+			schema: `{"type": "object", "properties": {"jobs": {"type": "array", "minItems": 3, "items": ` + jobSchema + `}}}`,
+			result: `{"jobs": [{"title": "a"}, {"title": 2}]}`,
+			want: []SchemaProblem{
+				{Path: "jobs", Kind: ProblemConstraint, Message: "expected at least 3 items, got 2"},
+				{Path: "jobs[1].title", Kind: ProblemType},
+			},
+		},
+		{
+			name:   "maxItems",
+			schema: `{"type": "array", "maxItems": 1}`,
+			result: `[1, 2]`,
+			want:   []SchemaProblem{{Path: "", Kind: ProblemConstraint}},
+		},
+		{
+			name:   "anyOf matches one branch",
+			schema: `{"anyOf": [{"type": "string"}, {"type": "integer"}]}`,
+			result: `7`,
+		},
+		{
+			name:   "anyOf matches no branch",
+			schema: `{"anyOf": [{"type": "string"}, {"type": "integer"}]}`,
+			result: `true`,
+			want:   []SchemaProblem{{Path: "", Kind: ProblemType, Message: "does not match any allowed schema"}},
+		},
+		{
+			name:   "oneOf with nested problems",
+			schema: `{"oneOf": [{"type": "object", "required": ["a"]}, {"type": "object", "required": ["b"]}]}`,
+			result: `{"c": 1}`,
+			want:   []SchemaProblem{{Path: "", Kind: ProblemType}},
+		},
+		{
+			name:   "local $ref",
+			schema: `{"$defs": {"Job": ` + jobSchema + `}, "type": "array", "items": {"$ref": "#/$defs/Job"}}`,
+			result: `[{"title": "a"}, {}]`,
+			want:   []SchemaProblem{{Path: "[1].title", Kind: ProblemMissing}},
+		},
+		{
+			name:   "cyclic $ref",
+			schema: `{"$defs": {"A": {"$ref": "#/$defs/B"}, "B": {"$ref": "#/$defs/A"}}, "properties": {"x": {"$ref": "#/$defs/A"}}}`,
+			result: `{"x": 1}`,
+		},
+		{
+			name:   "unresolvable $ref",
+			schema: `{"properties": {"x": {"$ref": "#/$defs/Missing"}}}`,
+			result: `{"x": 1}`,
+		},
+		{
+			name:   "remote $ref",
+			schema: `{"properties": {"x": {"$ref": "https://example.com/schema.json"}}}`,
+			result: `{"x": 1}`,
+		},
+		{
+			name:   "validation envelope is unwrapped",
+			schema: jobSchema,
+			result: `{"data": {"title": 1}, "schema_validation": {"valid": false}}`,
+			want:   []SchemaProblem{{Path: "title", Kind: ProblemType}},
+		},
+		{
+			name:   "non-object schema is not checked",
+			schema: `"a list of job titles"`,
+			result: `42`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var schema, result interface{}
+			if err := json.Unmarshal([]byte(tt.schema), &schema); err != nil {
+				t.Fatalf("schema: %v", err)
+			}
+			if err := json.Unmarshal([]byte(tt.result), &result); err != nil {
+				t.Fatalf("result: %v", err)
+			}
+
+			err := ValidateResult(&ScrapeResponse{RequestID: "req_1", Result: result}, schema)
+			var got []SchemaProblem
+			if err != nil {
+				var sve *SchemaValidationError
+				if !errors.As(err, &sve) {
+					t.Fatalf("ValidateResult = %v, want a *SchemaValidationError", err)
+				}
+				if sve.RequestID != "req_1" {
+					t.Errorf("RequestID = %q, want req_1", sve.RequestID)
+				}
+				got = sve.Problems
+			}
+
+			if len(got) != len(tt.want) {
+				t.Fatalf("problems = %v, want %v", got, tt.want)
+			}
+			for i, want := range tt.want {
+				if got[i].Path != want.Path || got[i].Kind != want.Kind {
+					t.Errorf("problem %d = %+v, want path %q kind %s", i, got[i], want.Path, want.Kind)
+				}
+				if want.Message != "" && got[i].Message != want.Message {
+					t.Errorf("problem %d message = %q, want %q", i, got[i].Message, want.Message)
+				}
+			}
+		})
+	}
+}
+
+func TestValidateResultTypedValues(t *testing.T) {
+	type job struct {
+		Title  string   `json:"title"`
+		Salary *float64 `json:"salary"`
+	}
+	schema := map[string]interface{}{
+		"type":     "array",
+		"items":    map[string]interface{}{"type": "object", "required": []string{"title", "salary"}},
+		"minItems": 1,
+	}
+	resp := &ScrapeResponse{RequestID: "req_1", Result: []job{{Title: "a"}}}
+	if err := ValidateResult(resp, schema); err != nil {
+		t.Errorf("ValidateResult = %v, want nil", err)
+	}
+
+	resp.Result = []job{}
+	err := ValidateResult(resp, schema)
+	var sve *SchemaValidationError
+	if !errors.As(err, &sve) {
+		t.Fatalf("ValidateResult = %v, want a *SchemaValidationError", err)
+	}
+	want := []SchemaProblem{{Path: "", Kind: ProblemConstraint, Message: "expected at least 1 items, got 0"}}
+	if !reflect.DeepEqual(sve.Problems, want) {
+		t.Errorf("problems = %+v, want %+v", sve.Problems, want)
+	}
+}
@@ -0,0 +1,136 @@
+package scrapeapitest
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	scrapeapi "github.com/dir01/scrapeapi/sdk/go"
+)
+
+// FakeClient is an in-process scrapeapi.ScrapeClient that finishes every job
+// immediately, for unit tests that do not need HTTP at all.
+//
+//	fake := &scrapeapitest.FakeClient{Handler: func(req *scrapeapi.ScrapeRequest) (*scrapeapi.ScrapeResponse, error) {
+//		return &scrapeapi.ScrapeResponse{Status: scrapeapi.StatusCompleted, Result: myResult}, nil
+//	}}
+//	svc := NewService(fake) // NewService accepts a scrapeapi.ScrapeClient
+type FakeClient struct {
+	// Handler returns the finished job for a submitted request. Its RequestID
+	// and Status are filled in if empty, and a returned error fails StartScrape. When nil,
+	// every job completes with a nil result.
+	Handler func(req *scrapeapi.ScrapeRequest) (*scrapeapi.ScrapeResponse, error)
+
+	mu       sync.Mutex
+	seq      int
+	jobs     map[string]*scrapeapi.ScrapeResponse
+	requests []*scrapeapi.ScrapeRequest
+}
+
+var _ scrapeapi.ScrapeClient = (*FakeClient)(nil)
+
+// StartScrape validates req and records it, returning the job as queued
+func (f *FakeClient) StartScrape(ctx context.Context, req *scrapeapi.ScrapeRequest, opts ...scrapeapi.RequestOption) (*scrapeapi.ScrapeResponse, error) {
+	if err := req.Validate(); err != nil {
+		return nil, err
+	}
+
+	final := &scrapeapi.ScrapeResponse{Status: scrapeapi.StatusCompleted}
+	if f.Handler != nil {
+		var err error
+		if final, err = f.Handler(req); err != nil {
+			return nil, err
+		}
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.seq++
+	if final.RequestID == "" {
+		final.RequestID = fmt.Sprintf("fake-%d", f.seq)
+	}
+	if final.Status == "" {
+		final.Status = scrapeapi.StatusCompleted
+	}
+	if final.Graph == "" {
+		final.Graph = req.Graph
+	}
+	if f.jobs == nil {
+		f.jobs = map[string]*scrapeapi.ScrapeResponse{}
+	}
+	f.jobs[final.RequestID] = final
+	f.requests = append(f.requests, req)
+
+	return &scrapeapi.ScrapeResponse{RequestID: final.RequestID, Status: scrapeapi.StatusQueued, Graph: final.Graph}, nil
+}
+
+// GetScrape returns the finished job, or a 404 *scrapeapi.APIError for an unknown ID
+func (f *FakeClient) GetScrape(ctx context.Context, requestID string, opts ...scrapeapi.RequestOption) (*scrapeapi.ScrapeResponse, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	resp, ok := f.jobs[requestID]
+	if !ok {
+		return nil, &scrapeapi.APIError{
+			StatusCode: http.StatusNotFound,
+			Status:     "404 Not Found",
+			Code:       "not_found",
+			Message:    "job " + requestID + " not found",
+			RequestID:  requestID,
+		}
+	}
+	out := *resp
+	return &out, nil
+}
+
+// WaitForCompletion returns the finished job like GetScrape, failing with a
+// *scrapeapi.JobError if it did not complete. A WithProgress callback is
+// called once, with the finished job.
+func (f *FakeClient) WaitForCompletion(ctx context.Context, requestID string, pollInterval time.Duration, opts ...scrapeapi.WaitOption) (*scrapeapi.ScrapeResponse, error) {
+	resp, err := f.finished(ctx, requestID)
+	if resp != nil {
+		scrapeapi.ApplyWaitOptions(resp, nil, opts...)
+	}
+	return resp, err
+}
+
+// ScrapeAndWait starts a job and returns its finished state, checking the
+// result against the output schema when WithResultValidation is given
+func (f *FakeClient) ScrapeAndWait(ctx context.Context, req *scrapeapi.ScrapeRequest, opts ...scrapeapi.WaitOption) (*scrapeapi.ScrapeResponse, error) {
+	started, err := f.StartScrape(ctx, req)
+	if err != nil {
+		return nil, fmt.Errorf("start scrape: %w", err)
+	}
+	resp, err := f.finished(ctx, started.RequestID)
+	if resp == nil {
+		return nil, err
+	}
+	if checkErr := scrapeapi.ApplyWaitOptions(resp, req, opts...); err == nil {
+		err = checkErr
+	}
+	return resp, err
+}
+
+// finished returns the stored job, with a *scrapeapi.JobError if it did not complete
+func (f *FakeClient) finished(ctx context.Context, requestID string) (*scrapeapi.ScrapeResponse, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	resp, err := f.GetScrape(ctx, requestID)
+	if err != nil {
+		return nil, err
+	}
+	switch resp.Status {
+	case scrapeapi.StatusFailed, scrapeapi.StatusCanceled:
+		return resp, &scrapeapi.JobError{RequestID: resp.RequestID, Status: resp.Status, Code: resp.ErrorCode, Message: resp.Error}
+	}
+	return resp, nil
+}
+
+// Requests returns the requests submitted so far, in order
+func (f *FakeClient) Requests() []*scrapeapi.ScrapeRequest {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return append([]*scrapeapi.ScrapeRequest(nil), f.requests...)
+}
@@ -0,0 +1,296 @@
+package scrapeapitest
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"math/rand"
+	"sort"
+	"strings"
+	"time"
+)
+
+// maxFakeDepth bounds nesting so recursive schemas terminate
+const maxFakeDepth = 8
+
+// FakeResult generates a result matching schema, which may be any value that
+// marshals to a JSON Schema object, e.g. the OutputSchema of a request. The
+// same schema and seed always produce the same result, decoded generically
+// like a real job result.
+//
+// Enum, const and examples values are picked from, formats such as date-time,
+// email and uri are honored, and numeric, length and item-count bounds are
+// respected. Optional properties are always included.
+//
+//	srv := scrapeapitest.NewServer(scrapeapitest.WithLifecycleFunc(func(req *scrapeapi.ScrapeRequest) scrapeapitest.Lifecycle {
+//		result, _ := scrapeapitest.FakeResult(req.OutputSchema, 1)
+//		return scrapeapitest.Lifecycle{Result: result}
+//	}))
+func FakeResult(schema interface{}, seed int64) (interface{}, error) {
+	raw, err := json.Marshal(schema)
+	if err != nil {
+		return nil, fmt.Errorf("marshal schema: %w", err)
+	}
+	var root map[string]interface{}
+	if err := json.Unmarshal(raw, &root); err != nil {
+		return nil, fmt.Errorf("schema is not a JSON Schema object: %w", err)
+	}
+
+	g := &faker{root: root, rnd: rand.New(rand.NewSource(seed))}
+	return g.value("value", root, 0), nil
+}
+
+type faker struct {
+	root map[string]interface{}
+	rnd  *rand.Rand
+}
+
+func (g *faker) value(name string, schema map[string]interface{}, depth int) interface{} {
+	if depth > 2*maxFakeDepth {
+		// A recursive schema requiring itself cannot be satisfied; stop here
+		return nil
+	}
+	schema = g.resolve(schema)
+
+	if c, ok := schema["const"]; ok {
+		return c
+	}
+	if enum, ok := schema["enum"].([]interface{}); ok && len(enum) > 0 {
+		return enum[g.rnd.Intn(len(enum))]
+	}
+	if examples, ok := schema["examples"].([]interface{}); ok && len(examples) > 0 {
+		return examples[g.rnd.Intn(len(examples))]
+	}
+	for _, key := range []string{"anyOf", "oneOf", "allOf"} {
+		if branches, ok := schema[key].([]interface{}); ok && len(branches) > 0 {
+			// allOf is approximated by its first branch
+			pick := 0
+			if key != "allOf" {
+				pick = g.rnd.Intn(len(branches))
+			}
+			if branch, ok := branches[pick].(map[string]interface{}); ok {
+				return g.value(name, branch, depth)
+			}
+		}
+	}
+
+	switch g.pickType(schema) {
+	case "object":
+		return g.object(schema, depth)
+	case "array":
+		return g.array(name, schema, depth)
+	case "string":
+		return g.string(name, schema)
+	case "integer":
+		return math.Round(g.number(schema, true))
+	case "number":
+		return g.number(schema, false)
+	case "boolean":
+		return g.rnd.Intn(2) == 1
+	case "null":
+		return nil
+	}
+	return nil
+}
+
+// pickType chooses the JSON type to generate, preferring non-null types and
+// inferring one from the keywords present when "type" is absent
+func (g *faker) pickType(schema map[string]interface{}) string {
+	var types []string
+	switch t := schema["type"].(type) {
+	case string:
+		types = []string{t}
+	case []interface{}:
+		for _, x := range t {
+			if s, ok := x.(string); ok && s != "null" {
+				types = append(types, s)
+			}
+		}
+		if len(types) == 0 {
+			return "null"
+		}
+	}
+	if len(types) > 0 {
+		return types[g.rnd.Intn(len(types))]
+	}
+
+	switch {
+	case schema["properties"] != nil:
+		return "object"
+	case schema["items"] != nil:
+		return "array"
+	}
+	return "string"
+}
+
+func (g *faker) object(schema map[string]interface{}, depth int) interface{} {
+	obj := map[string]interface{}{}
+	props, _ := schema["properties"].(map[string]interface{})
+	if depth >= maxFakeDepth {
+		// Only required properties are generated this deep, and without nesting
+		props = requiredOnly(schema, props)
+	}
+	names := make([]string, 0, len(props))
+	for name := range props {
+		names = append(names, name)
+	}
+	// Sorted so the same seed consumes random numbers in the same order
+	sort.Strings(names)
+	for _, name := range names {
+		propSchema, ok := props[name].(map[string]interface{})
+		if !ok {
+			continue
+		}
+		obj[name] = g.value(name, propSchema, depth+1)
+	}
+	return obj
+}
+
+func (g *faker) array(name string, schema map[string]interface{}, depth int) interface{} {
+	lo, hi := 1, 3
+	if n, ok := schema["minItems"].(float64); ok {
+		lo = int(n)
+		if hi < lo {
+			hi = lo
+		}
+	}
+	if n, ok := schema["maxItems"].(float64); ok {
+		hi = int(n)
+		if lo > hi {
+			lo = hi
+		}
+	}
+	if depth >= maxFakeDepth {
+		hi = lo
+	}
+
+	items, _ := schema["items"].(map[string]interface{})
+	n := lo + g.rnd.Intn(hi-lo+1)
+	arr := make([]interface{}, n)
+	for i := range arr {
+		if items == nil {
+			arr[i] = g.string(name, nil)
+			continue
+		}
+		arr[i] = g.value(strings.TrimSuffix(name, "s"), items, depth+1)
+	}
+	return arr
+}
+
+func (g *faker) string(name string, schema map[string]interface{}) interface{} {
+	var s string
+	format, _ := schema["format"].(string)
+	switch format {
+	case "date-time":
+		s = g.time().Format(time.RFC3339)
+	case "date":
+		s = g.time().Format(time.DateOnly)
+	case "time":
+		s = g.time().Format(time.TimeOnly)
+	case "email":
+		s = fmt.Sprintf("user%d@example.com", g.rnd.Intn(1000))
+	case "uri", "url", "iri":
+		s = fmt.Sprintf("https://example.com/%s/%d", slug(name), g.rnd.Intn(1000))
+	case "hostname":
+		s = fmt.Sprintf("host%d.example.com", g.rnd.Intn(100))
+	case "ipv4":
+		s = fmt.Sprintf("192.0.2.%d", 1+g.rnd.Intn(254))
+	case "uuid":
+		b := make([]byte, 16)
+		g.rnd.Read(b)
+		s = fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:])
+	default:
+		s = fmt.Sprintf("%s %d", name, g.rnd.Intn(1000))
+	}
+	if format != "" {
+		return s
+	}
+
+	if n, ok := schema["minLength"].(float64); ok {
+		for len(s) < int(n) {
+			s += "x"
+		}
+	}
+	if n, ok := schema["maxLength"].(float64); ok && len(s) > int(n) {
+		s = s[:int(n)]
+	}
+	return s
+}
+
+func (g *faker) number(schema map[string]interface{}, integer bool) float64 {
+	step := 0.01
+	if integer {
+		step = 1
+	}
+	lo, hi := 0.0, 1000.0
+	if n, ok := schema["minimum"].(float64); ok {
+		lo = n
+	}
+	if n, ok := schema["exclusiveMinimum"].(float64); ok {
+		lo = n + step
+	}
+	if n, ok := schema["maximum"].(float64); ok {
+		hi = n
+	}
+	if n, ok := schema["exclusiveMaximum"].(float64); ok {
+		hi = n - step
+	}
+	if hi < lo {
+		hi = lo
+	}
+
+	if integer {
+		lo, hi = math.Ceil(lo), math.Floor(hi)
+		if hi < lo {
+			return lo
+		}
+		return lo + float64(g.rnd.Int63n(int64(hi-lo)+1))
+	}
+	// Two decimals keep generated prices and scores readable
+	return math.Round((lo+g.rnd.Float64()*(hi-lo))*100) / 100
+}
+
+// time returns a timestamp in 2024, fixed so results do not depend on the clock
+func (g *faker) time() time.Time {
+	base := time.Date(2025, time.January, 1, 0, 0, 0, 0, time.UTC)
+	return base.Add(-time.Duration(g.rnd.Int63n(int64(365 * 24 * time.Hour)))).Truncate(time.Second)
+}
+
+// resolve follows local "#/..." references, giving up on cycles and remote refs
+func (g *faker) resolve(schema map[string]interface{}) map[string]interface{} {
+	for range 32 {
+		ref, ok := schema["$ref"].(string)
+		if !ok || !strings.HasPrefix(ref, "#") {
+			return schema
+		}
+		var node interface{} = g.root
+		for _, part := range strings.Split(strings.TrimPrefix(ref, "#"), "/") {
+			if part == "" {
+				continue
+			}
+			m, _ := node.(map[string]interface{})
+			node = m[part]
+		}
+		next, ok := node.(map[string]interface{})
+		if !ok {
+			return schema
+		}
+		schema = next
+	}
+	return schema
+}
+
+func requiredOnly(schema, props map[string]interface{}) map[string]interface{} {
+	required, _ := schema["required"].([]interface{})
+	out := map[string]interface{}{}
+	for _, r := range required {
+		if name, ok := r.(string); ok {
+			out[name] = props[name]
+		}
+	}
+	return out
+}
+
+func slug(name string) string {
+	return strings.ToLower(strings.ReplaceAll(name, " ", "-"))
+}
@@ -0,0 +1,421 @@
+// Package scrapeapitest provides an in-memory ScrapeAPI server for testing
+// code that uses the SDK without a live backend.
+//
+//	srv := scrapeapitest.NewServer(scrapeapitest.WithLifecycle(scrapeapitest.Lifecycle{
+//		RunningPolls: 2,
+//		Result:       map[string]interface{}{"jobs": []interface{}{}},
+//	}))
+//	defer srv.Close()
+//
+//	client := srv.SDKClient()
+//	resp, err := client.ScrapeAndWait(ctx, req, scrapeapi.WithPollInterval(time.Millisecond))
+package scrapeapitest
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strconv"
+	"sync"
+	"time"
+
+	scrapeapi "github.com/dir01/scrapeapi/sdk/go"
+)
+
+// Lifecycle scripts how a job progresses as it is polled. The zero value
+// completes on the first poll with a nil result.
+type Lifecycle struct {
+	// QueuedPolls is how many polls report the job as queued
+	QueuedPolls int
+	// RunningPolls is how many polls after those report the job as running
+	RunningPolls int
+	// Result is the result of the completed job
+	Result interface{}
+	// Error fails the job with this message instead of completing it
+	Error string
+	// ErrorCode is reported with Error
+	ErrorCode scrapeapi.JobErrorCode
+}
+
+// Option is a functional option for configuring a Server
+type Option func(*Server)
+
+// WithLifecycle makes every job follow l
+func WithLifecycle(l Lifecycle) Option {
+	return WithLifecycleFunc(func(*scrapeapi.ScrapeRequest) Lifecycle { return l })
+}
+
+// WithLifecycleFunc picks the lifecycle of each job from its request,
+// e.g. to fail jobs for one URL only
+func WithLifecycleFunc(fn func(req *scrapeapi.ScrapeRequest) Lifecycle) Option {
+	return func(s *Server) {
+		s.lifecycle = fn
+	}
+}
+
+// WithLatency delays every response by d
+func WithLatency(d time.Duration) Option {
+	return func(s *Server) {
+		s.latency = d
+	}
+}
+
+// Server is an in-memory implementation of the job endpoints of the API:
+// POST /v1/scrape, GET /v1/scrape (listing), GET /v1/scrape/{id},
+// GET /v1/scrape/{id}/logs, POST /v1/scrape/{id}/cancel and
+// DELETE /v1/scrape/{id}. Submitted requests are validated like the SDK does.
+type Server struct {
+	*httptest.Server
+
+	lifecycle func(req *scrapeapi.ScrapeRequest) Lifecycle
+	latency   time.Duration
+
+	mu       sync.Mutex
+	seq      int
+	jobs     map[string]*job
+	requests []*scrapeapi.ScrapeRequest
+	failures []int
+}
+
+type job struct {
+	req       *scrapeapi.ScrapeRequest
+	lifecycle Lifecycle
+	polls     int
+	canceled  bool
+	createdAt time.Time
+}
+
+// NewServer starts a server; call Close when done
+func NewServer(opts ...Option) *Server {
+	s := &Server{
+		lifecycle: func(*scrapeapi.ScrapeRequest) Lifecycle { return Lifecycle{} },
+		jobs:      map[string]*job{},
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /v1/scrape", s.handleStart)
+	mux.HandleFunc("GET /v1/scrape", s.handleList)
+	mux.HandleFunc("GET /v1/scrape/{id}", s.handleGet)
+	mux.HandleFunc("GET /v1/scrape/{id}/logs", s.handleLogs)
+	mux.HandleFunc("POST /v1/scrape/{id}/cancel", s.handleCancel)
+	mux.HandleFunc("DELETE /v1/scrape/{id}", s.handleDelete)
+	s.Server = httptest.NewServer(s.intercept(mux))
+	return s
+}
+
+// SDKClient returns a scrapeapi client for the server with tracing disabled
+func (s *Server) SDKClient(opts ...scrapeapi.ClientOption) *scrapeapi.Client {
+	opts = append([]scrapeapi.ClientOption{scrapeapi.WithTracingDisabled()}, opts...)
+	return scrapeapi.NewClient(s.URL, opts...)
+}
+
+// FailNext makes the next n API calls fail with the HTTP status code, e.g.
+// http.StatusServiceUnavailable to exercise retries
+func (s *Server) FailNext(n, statusCode int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for i := 0; i < n; i++ {
+		s.failures = append(s.failures, statusCode)
+	}
+}
+
+// Requests returns the requests submitted so far, in order
+func (s *Server) Requests() []*scrapeapi.ScrapeRequest {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]*scrapeapi.ScrapeRequest(nil), s.requests...)
+}
+
+// Polls returns how often the job has been polled
+func (s *Server) Polls(requestID string) int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if j, ok := s.jobs[requestID]; ok {
+		return j.polls
+	}
+	return 0
+}
+
+// intercept applies the configured latency and injected failures
+func (s *Server) intercept(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if s.latency > 0 {
+			select {
+			case <-time.After(s.latency):
+			case <-r.Context().Done():
+				return
+			}
+		}
+
+		s.mu.Lock()
+		status := 0
+		if len(s.failures) > 0 {
+			status, s.failures = s.failures[0], s.failures[1:]
+		}
+		s.mu.Unlock()
+		if status != 0 {
+			writeError(w, status, "injected_failure", "injected failure")
+			return
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+func (s *Server) handleStart(w http.ResponseWriter, r *http.Request) {
+	var req scrapeapi.ScrapeRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid_json", fmt.Sprintf("decode request: %v", err))
+		return
+	}
+	if err := req.Validate(); err != nil {
+		writeError(w, http.StatusUnprocessableEntity, "invalid_request", err.Error())
+		return
+	}
+
+	s.mu.Lock()
+	s.seq++
+	id := fmt.Sprintf("job-%d", s.seq)
+	j := &job{req: &req, lifecycle: s.lifecycle(&req), createdAt: time.Now()}
+	s.jobs[id] = j
+	s.requests = append(s.requests, &req)
+	resp := j.response(id, scrapeapi.StatusQueued)
+	s.mu.Unlock()
+
+	writeJSON(w, http.StatusOK, resp)
+}
+
+// handleList lists jobs newest first, honoring the ListOptions filters; the
+// cursor is the offset of the next page
+func (s *Server) handleList(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	var createdAfter time.Time
+	if v := q.Get("created_after"); v != "" {
+		var err error
+		if createdAfter, err = time.Parse(time.RFC3339, v); err != nil {
+			writeError(w, http.StatusBadRequest, "invalid_request", "created_after: "+err.Error())
+			return
+		}
+	}
+	limit, _ := strconv.Atoi(q.Get("limit"))
+	if limit <= 0 {
+		limit = 20
+	}
+	offset, _ := strconv.Atoi(q.Get("cursor"))
+
+	s.mu.Lock()
+	var items []scrapeapi.ScrapeResponse
+	for n := s.seq; n > 0; n-- {
+		id := fmt.Sprintf("job-%d", n)
+		j, ok := s.jobs[id]
+		if !ok {
+			continue
+		}
+		resp := j.response(id, j.status())
+		switch {
+		case q.Get("status") != "" && string(resp.Status) != q.Get("status"),
+			q.Get("graph") != "" && string(resp.Graph) != q.Get("graph"),
+			q.Get("tag") != "" && !slices.Contains(resp.Tags, q.Get("tag")),
+			!createdAfter.IsZero() && !j.createdAt.After(createdAfter):
+			continue
+		}
+		items = append(items, *resp)
+	}
+	s.mu.Unlock()
+
+	list := scrapeapi.ScrapeList{Items: []scrapeapi.ScrapeResponse{}}
+	if offset < len(items) {
+		end := min(offset+limit, len(items))
+		list.Items = items[offset:end]
+		if end < len(items) {
+			list.NextCursor = strconv.Itoa(end)
+		}
+	}
+	writeJSON(w, http.StatusOK, list)
+}
+
+func (s *Server) handleGet(w http.ResponseWriter, r *http.Request) {
+	id := r.PathValue("id")
+
+	s.mu.Lock()
+	j, ok := s.jobs[id]
+	var resp *scrapeapi.ScrapeResponse
+	if ok {
+		j.polls++
+		resp = j.response(id, j.status())
+	}
+	s.mu.Unlock()
+
+	if !ok {
+		writeError(w, http.StatusNotFound, "not_found", "job "+id+" not found")
+		return
+	}
+	writeJSON(w, http.StatusOK, resp)
+}
+
+// handleLogs serves a log made up from the job's status; like a poll, reading
+// it moves the job along its lifecycle
+func (s *Server) handleLogs(w http.ResponseWriter, r *http.Request) {
+	id := r.PathValue("id")
+	q := r.URL.Query()
+	after, _ := strconv.ParseInt(q.Get("after"), 10, 64)
+	tail, _ := strconv.Atoi(q.Get("tail"))
+
+	s.mu.Lock()
+	j, ok := s.jobs[id]
+	var logs scrapeapi.ScrapeLogs
+	if ok {
+		j.polls++
+		logs.Status = j.status()
+		for _, entry := range j.logs(logs.Status) {
+			if entry.Sequence > after && logLevelAtLeast(entry.Level, scrapeapi.LogLevel(q.Get("level"))) {
+				logs.Entries = append(logs.Entries, entry)
+			}
+		}
+	}
+	s.mu.Unlock()
+
+	if !ok {
+		writeError(w, http.StatusNotFound, "not_found", "job "+id+" not found")
+		return
+	}
+	if tail > 0 && len(logs.Entries) > tail {
+		logs.Entries = logs.Entries[len(logs.Entries)-tail:]
+	}
+	writeJSON(w, http.StatusOK, logs)
+}
+
+func (s *Server) handleCancel(w http.ResponseWriter, r *http.Request) {
+	id := r.PathValue("id")
+
+	s.mu.Lock()
+	j, ok := s.jobs[id]
+	var resp *scrapeapi.ScrapeResponse
+	if ok {
+		if status := j.status(); status == scrapeapi.StatusQueued || status == scrapeapi.StatusRunning {
+			j.canceled = true
+		}
+		resp = j.response(id, j.status())
+	}
+	s.mu.Unlock()
+
+	if !ok {
+		writeError(w, http.StatusNotFound, "not_found", "job "+id+" not found")
+		return
+	}
+	writeJSON(w, http.StatusOK, resp)
+}
+
+func (s *Server) handleDelete(w http.ResponseWriter, r *http.Request) {
+	id := r.PathValue("id")
+
+	s.mu.Lock()
+	_, ok := s.jobs[id]
+	delete(s.jobs, id)
+	s.mu.Unlock()
+
+	if !ok {
+		writeError(w, http.StatusNotFound, "not_found", "job "+id+" not found")
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// status is the status of the job after its polls so far
+func (j *job) status() scrapeapi.Status {
+	l := j.lifecycle
+	switch {
+	case j.canceled:
+		return scrapeapi.StatusCanceled
+	case j.polls <= l.QueuedPolls:
+		return scrapeapi.StatusQueued
+	case j.polls <= l.QueuedPolls+l.RunningPolls:
+		return scrapeapi.StatusRunning
+	case l.Error != "":
+		return scrapeapi.StatusFailed
+	default:
+		return scrapeapi.StatusCompleted
+	}
+}
+
+// logs is the log of a job that has reached status
+func (j *job) logs(status scrapeapi.Status) []scrapeapi.LogEntry {
+	var entries []scrapeapi.LogEntry
+	add := func(level scrapeapi.LogLevel, message string, fields map[string]interface{}) {
+		entries = append(entries, scrapeapi.LogEntry{
+			Sequence:  int64(len(entries) + 1),
+			Timestamp: j.createdAt.Add(time.Duration(len(entries)) * time.Second),
+			Level:     level,
+			Message:   message,
+			Fields:    fields,
+		})
+	}
+
+	add(scrapeapi.LogLevelInfo, "job queued", map[string]interface{}{"graph": string(j.req.Graph)})
+	switch status {
+	case scrapeapi.StatusQueued:
+		return entries
+	case scrapeapi.StatusCanceled:
+		add(scrapeapi.LogLevelWarn, "job canceled", nil)
+		return entries
+	}
+	url := ""
+	if j.req.WebsiteURL != nil {
+		url = *j.req.WebsiteURL
+	}
+	add(scrapeapi.LogLevelInfo, "fetching page", map[string]interface{}{"url": url})
+	add(scrapeapi.LogLevelDebug, "calling LLM", map[string]interface{}{"attempt": 1})
+	switch status {
+	case scrapeapi.StatusCompleted:
+		add(scrapeapi.LogLevelInfo, "job completed", nil)
+	case scrapeapi.StatusFailed:
+		var fields map[string]interface{}
+		if j.lifecycle.ErrorCode != "" {
+			fields = map[string]interface{}{"error_code": string(j.lifecycle.ErrorCode)}
+		}
+		add(scrapeapi.LogLevelError, "job failed: "+j.lifecycle.Error, fields)
+	}
+	return entries
+}
+
+// logLevelAtLeast reports whether level passes a min level filter
+func logLevelAtLeast(level, min scrapeapi.LogLevel) bool {
+	order := []scrapeapi.LogLevel{scrapeapi.LogLevelDebug, scrapeapi.LogLevelInfo, scrapeapi.LogLevelWarn, scrapeapi.LogLevelError}
+	return min == "" || slices.Index(order, level) >= slices.Index(order, min)
+}
+
+func (j *job) response(id string, status scrapeapi.Status) *scrapeapi.ScrapeResponse {
+	resp := &scrapeapi.ScrapeResponse{
+		RequestID:  id,
+		Status:     status,
+		Graph:      j.req.Graph,
+		UserPrompt: j.req.UserPrompt,
+		WebsiteURL: j.req.WebsiteURL,
+		Sources:    j.req.Sources,
+		Tags:       j.req.Tags,
+		CreatedAt:  &j.createdAt,
+	}
+	switch status {
+	case scrapeapi.StatusCompleted:
+		resp.Result = j.lifecycle.Result
+	case scrapeapi.StatusFailed:
+		resp.Error = j.lifecycle.Error
+		resp.ErrorCode = j.lifecycle.ErrorCode
+	}
+	return resp
+}
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+func writeError(w http.ResponseWriter, status int, code, message string) {
+	writeJSON(w, status, map[string]string{"code": code, "message": message})
+}
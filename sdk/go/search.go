@@ -0,0 +1,63 @@
+package scrapeapi
+
+// SearchEngine is the web search backend of the search graph
+type SearchEngine string
+
+// Search engines offered by the API
+const (
+	SearchGoogle     SearchEngine = "google"
+	SearchBing       SearchEngine = "bing"
+	SearchDuckDuckGo SearchEngine = "duckduckgo"
+)
+
+// SearchTimeRange restricts search results to recently published pages
+type SearchTimeRange string
+
+// Time ranges for SearchConfig
+const (
+	SearchPastDay   SearchTimeRange = "day"
+	SearchPastWeek  SearchTimeRange = "week"
+	SearchPastMonth SearchTimeRange = "month"
+	SearchPastYear  SearchTimeRange = "year"
+)
+
+// SafeSearch is the explicit-content filter level of a search
+type SafeSearch string
+
+// Safe search levels for SearchConfig
+const (
+	SafeSearchOff      SafeSearch = "off"
+	SafeSearchModerate SafeSearch = "moderate"
+	SafeSearchStrict   SafeSearch = "strict"
+)
+
+// SearchConfig pins down how the search graph queries the web, so repeated
+// search-and-scrape jobs see the same results. Zero fields use the server defaults.
+type SearchConfig struct {
+	Engine SearchEngine `json:"engine,omitempty"`
+	// Region is the ISO 3166-1 alpha-2 code of the market to search, e.g. "DE"
+	Region string `json:"region,omitempty"`
+	// Language is the BCP 47 language of the results, e.g. "de" or "pt-BR"
+	Language   string          `json:"language,omitempty"`
+	TimeRange  SearchTimeRange `json:"time_range,omitempty"`
+	SafeSearch SafeSearch      `json:"safe_search,omitempty"`
+}
+
+func (s *SearchConfig) validate(v *validator, field string) {
+	switch s.Engine {
+	case "", SearchGoogle, SearchBing, SearchDuckDuckGo:
+	default:
+		v.addf(field+".engine", "unknown search engine %q", s.Engine)
+	}
+	switch s.TimeRange {
+	case "", SearchPastDay, SearchPastWeek, SearchPastMonth, SearchPastYear:
+	default:
+		v.addf(field+".time_range", "unknown time range %q", s.TimeRange)
+	}
+	switch s.SafeSearch {
+	case "", SafeSearchOff, SafeSearchModerate, SafeSearchStrict:
+	default:
+		v.addf(field+".safe_search", "unknown safe search level %q", s.SafeSearch)
+	}
+	v.checkCountry(field+".region", s.Region)
+}
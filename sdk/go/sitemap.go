@@ -0,0 +1,38 @@
+package scrapeapi
+
+import (
+	"fmt"
+	"regexp"
+)
+
+// SitemapConfig controls which pages of a sitemap the sitemap graph scrapes
+type SitemapConfig struct {
+	// Include keeps only page URLs matching at least one of these regular expressions
+	Include []string `json:"include,omitempty"`
+	// Exclude drops page URLs matching any of these regular expressions
+	Exclude []string `json:"exclude,omitempty"`
+	// Depth limits how many levels of nested sitemap indexes are followed; the server default applies when zero
+	Depth int `json:"depth,omitempty"`
+	// Concurrency is the number of pages scraped at once; the server default applies when zero
+	Concurrency int `json:"concurrency,omitempty"`
+}
+
+func (s *SitemapConfig) validate(v *validator, field string) {
+	v.checkPatterns(field+".include", s.Include)
+	v.checkPatterns(field+".exclude", s.Exclude)
+	if s.Depth < 0 {
+		v.addf(field+".depth", "must not be negative, got %d", s.Depth)
+	}
+	if s.Concurrency < 0 {
+		v.addf(field+".concurrency", "must not be negative, got %d", s.Concurrency)
+	}
+}
+
+// checkPatterns reports a field error for every pattern that is not a valid regular expression
+func (v *validator) checkPatterns(field string, patterns []string) {
+	for i, p := range patterns {
+		if _, err := regexp.Compile(p); err != nil {
+			v.addf(fmt.Sprintf("%s[%d]", field, i), "invalid pattern: %v", err)
+		}
+	}
+}
@@ -0,0 +1,119 @@
+package scrapeapi
+
+import "encoding/json"
+
+// Source is one page of a multi-source job, with options of its own
+type Source struct {
+	URL string `json:"url"`
+	// Headers are sent to this source only, on top of ScrapeRequest.Headers
+	Headers map[string]string `json:"headers,omitempty"`
+	// LoaderKwargs overrides ScrapeRequest.LoaderKwargs for this source
+	LoaderKwargs interface{} `json:"loader_kwargs,omitempty"`
+	// Weight ranks this source against the others when their values conflict;
+	// higher wins and the server default applies when zero
+	Weight float64 `json:"weight,omitempty"`
+}
+
+// URLs turns plain URLs into sources without options
+func URLs(urls ...string) []Source {
+	sources := make([]Source, len(urls))
+	for i, u := range urls {
+		sources[i] = Source{URL: u}
+	}
+	return sources
+}
+
+// MarshalJSON encodes a source without options as its bare URL, the form
+// servers without per-source options accept
+func (s Source) MarshalJSON() ([]byte, error) {
+	if len(s.Headers) == 0 && s.LoaderKwargs == nil && s.Weight == 0 {
+		return json.Marshal(s.URL)
+	}
+	type plain Source
+	return json.Marshal(plain(s))
+}
+
+// UnmarshalJSON accepts both a bare URL and a source object
+func (s *Source) UnmarshalJSON(data []byte) error {
+	var u string
+	if err := json.Unmarshal(data, &u); err == nil {
+		*s = Source{URL: u}
+		return nil
+	}
+	type plain Source
+	return json.Unmarshal(data, (*plain)(s))
+}
+
+func (s *Source) validate(v *validator, field string) {
+	v.checkURL(field, s.URL)
+	for name := range s.Headers {
+		if !validHeaderName(name) {
+			v.addf(field+".headers", "invalid header name %q", name)
+		}
+	}
+	if s.Weight < 0 {
+		v.addf(field+".weight", "must not be negative, got %g", s.Weight)
+	}
+}
+
+// MergeStrategy decides how results extracted from several sources are combined
+type MergeStrategy string
+
+// Merge strategies for MergeConfig
+const (
+	// MergeUnion keeps every item from every source
+	MergeUnion MergeStrategy = "union"
+	// MergePreferFirst takes each value from the first source, by order or weight, that has it
+	MergePreferFirst MergeStrategy = "prefer_first"
+	// MergeDedupeByKey keeps one item per value of MergeConfig.Key
+	MergeDedupeByKey MergeStrategy = "dedupe_by_key"
+)
+
+// MergeConfig controls how the results of a multi-source job are combined
+type MergeConfig struct {
+	Strategy MergeStrategy `json:"strategy"`
+	// Key is the item field identifying duplicates for MergeDedupeByKey
+	Key string `json:"key,omitempty"`
+}
+
+func (m *MergeConfig) validate(v *validator, field string) {
+	switch m.Strategy {
+	case MergeUnion, MergePreferFirst:
+		if m.Key != "" {
+			v.addf(field+".key", "is only used by the %s strategy", MergeDedupeByKey)
+		}
+	case MergeDedupeByKey:
+		if m.Key == "" {
+			v.addf(field+".key", "is required by the %s strategy", MergeDedupeByKey)
+		}
+	case "":
+		v.addf(field+".strategy", "is required")
+	default:
+		v.addf(field+".strategy", "unknown merge strategy %q", m.Strategy)
+	}
+}
+
+// sourceURLs returns the URLs of sources
+func sourceURLs(sources []Source) []string {
+	urls := make([]string, len(sources))
+	for i, s := range sources {
+		urls[i] = s.URL
+	}
+	return urls
+}
+
+// String returns the URL of the source
+func (s Source) String() string {
+	return s.URL
+}
+
+// AttributionFor returns the attribution of the item at path in the result,
+// for jobs requested with IncludeAttribution
+func (r *ScrapeResponse) AttributionFor(path string) (Attribution, bool) {
+	for _, a := range r.Attributions {
+		if a.Path == path {
+			return a, true
+		}
+	}
+	return Attribution{}, false
+}
@@ -0,0 +1,133 @@
+package scrapeapi
+
+import (
+	"context"
+	"encoding/hex"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Span attribute keys set by the client
+const (
+	attrRequestID  = attribute.Key("scrapeapi.request_id")
+	attrGraph      = attribute.Key("scrapeapi.graph")
+	attrWebsiteURL = attribute.Key("scrapeapi.website_url")
+	attrModel      = attribute.Key("scrapeapi.llm.model")
+	attrStatus     = attribute.Key("scrapeapi.status")
+	attrPollCount  = attribute.Key("scrapeapi.poll_count")
+	attrBatchSize  = attribute.Key("scrapeapi.batch.size")
+)
+
+// requestAttributes describes the job a request submits
+func requestAttributes(req *ScrapeRequest) []attribute.KeyValue {
+	attrs := []attribute.KeyValue{attrGraph.String(string(req.Graph))}
+	if req.WebsiteURL != nil {
+		attrs = append(attrs, attrWebsiteURL.String(redactURL(*req.WebsiteURL)))
+	}
+	if req.LLM != nil && req.LLM.Model != "" {
+		attrs = append(attrs, attrModel.String(req.LLM.Model))
+	}
+	return attrs
+}
+
+// recordResponse describes the state of a job on span and links span to the
+// server-side trace of the job, if the server reported one
+func recordResponse(span trace.Span, resp *ScrapeResponse) {
+	span.SetAttributes(
+		attrRequestID.String(resp.RequestID),
+		attrStatus.String(string(resp.Status)),
+	)
+	if resp.Model != "" {
+		span.SetAttributes(attrModel.String(resp.Model))
+	}
+
+	server := resp.ServerSpanContext()
+	if server.IsValid() && server.TraceID() != span.SpanContext().TraceID() {
+		span.AddLink(trace.Link{SpanContext: server})
+	}
+}
+
+// waitState tracks a job while WaitForCompletion waits for it, recording
+// polls and status transitions as events on the wait span
+type waitState struct {
+	span  trace.Span
+	last  *ScrapeResponse
+	polls int
+	// onChange is called on every status transition
+	onChange func(from Status, resp *ScrapeResponse)
+}
+
+// observe records an update of the job; polled is false for pushed updates
+func (s *waitState) observe(resp *ScrapeResponse, polled bool) {
+	if polled {
+		s.polls++
+		s.span.AddEvent("poll", trace.WithAttributes(
+			attribute.Int("poll.number", s.polls),
+			attrStatus.String(string(resp.Status)),
+		))
+	}
+
+	prev := s.last
+	s.last = resp
+	if prev != nil && prev.Status == resp.Status {
+		return
+	}
+
+	var from Status
+	if prev != nil {
+		from = prev.Status
+	}
+	s.span.AddEvent("status_change", trace.WithAttributes(
+		attribute.String("status.from", string(from)),
+		attribute.String("status.to", string(resp.Status)),
+	))
+	if s.onChange != nil {
+		s.onChange(from, resp)
+	}
+}
+
+// ServerSpanContext returns the span the server runs the job in, or an
+// invalid span context if the server did not report one. Jobs are submitted
+// with the caller's trace context, so the server span normally belongs to the
+// caller's trace; otherwise the client links its spans to it. The span is
+// only marked sampled if the server says it was.
+func (r *ScrapeResponse) ServerSpanContext() trace.SpanContext {
+	traceID, err := trace.TraceIDFromHex(r.TraceID)
+	if err != nil {
+		return trace.SpanContext{}
+	}
+	spanID, err := trace.SpanIDFromHex(r.SpanID)
+	if err != nil {
+		return trace.SpanContext{}
+	}
+	var flags trace.TraceFlags
+	if b, err := hex.DecodeString(r.TraceFlags); err == nil && len(b) == 1 {
+		flags = trace.TraceFlags(b[0])
+	}
+	return trace.NewSpanContext(trace.SpanContextConfig{
+		TraceID:    traceID,
+		SpanID:     spanID,
+		TraceFlags: flags,
+		Remote:     true,
+	})
+}
+
+// withTraceContext returns req carrying the propagated context of ctx in its
+// payload, for the server to continue the trace when it runs the job later.
+// req itself is not modified.
+func (c *Client) withTraceContext(ctx context.Context, req *ScrapeRequest) *ScrapeRequest {
+	if req.TraceContext != nil {
+		return req
+	}
+	carrier := propagation.MapCarrier{}
+	c.propagator.Inject(ctx, carrier)
+	if len(carrier) == 0 {
+		return req
+	}
+
+	traced := *req
+	traced.TraceContext = carrier
+	return &traced
+}
@@ -0,0 +1,50 @@
+package scrapeapi
+
+import (
+	"testing"
+
+	"go.opentelemetry.io/otel/trace"
+)
+
+func TestServerSpanContext(t *testing.T) {
+	const (
+		traceID = "4bf92f3577b34da6a3ce929d0e0e4736"
+		spanID  = "00f067aa0ba902b7"
+	)
+	tests := []struct {
+		name        string
+		resp        ScrapeResponse
+		wantValid   bool
+		wantSampled bool
+	}{
+		{"sampled", ScrapeResponse{TraceID: traceID, SpanID: spanID, TraceFlags: "01"}, true, true},
+		{"not sampled", ScrapeResponse{TraceID: traceID, SpanID: spanID, TraceFlags: "00"}, true, false},
+		{"flags not reported", ScrapeResponse{TraceID: traceID, SpanID: spanID}, true, false},
+		{"malformed flags", ScrapeResponse{TraceID: traceID, SpanID: spanID, TraceFlags: "sampled"}, true, false},
+		{"no span", ScrapeResponse{TraceID: traceID}, false, false},
+		{"malformed trace ID", ScrapeResponse{TraceID: "nope", SpanID: spanID, TraceFlags: "01"}, false, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sc := tt.resp.ServerSpanContext()
+			if sc.IsValid() != tt.wantValid {
+				t.Fatalf("IsValid = %v, want %v", sc.IsValid(), tt.wantValid)
+			}
+			if sc.IsSampled() != tt.wantSampled {
+				t.Errorf("IsSampled = %v, want %v", sc.IsSampled(), tt.wantSampled)
+			}
+			if tt.wantValid && (!sc.IsRemote() || sc.TraceID() != mustTraceID(t, traceID)) {
+				t.Errorf("span context = %+v, want the remote span of trace %s", sc, traceID)
+			}
+		})
+	}
+}
+
+func mustTraceID(t *testing.T, h string) trace.TraceID {
+	t.Helper()
+	id, err := trace.TraceIDFromHex(h)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return id
+}
@@ -0,0 +1,73 @@
+package scrapeapi
+
+import (
+	"os"
+	"reflect"
+	"slices"
+	"strings"
+	"testing"
+
+	"gopkg.in/yaml.v3"
+)
+
+// TestHandwrittenSchemas checks that the hand-written wire types send the
+// fields the OpenAPI document lists, no more and no less
+func TestHandwrittenSchemas(t *testing.T) {
+	data, err := os.ReadFile("../../openapi.yaml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	type properties struct {
+		Properties map[string]yaml.Node `yaml:"properties"`
+	}
+	var doc struct {
+		Components struct {
+			Schemas struct {
+				ScrapeRequest properties `yaml:"ScrapeRequest"`
+				Source        struct {
+					OneOf []properties `yaml:"oneOf"`
+				} `yaml:"Source"`
+			} `yaml:"schemas"`
+		} `yaml:"components"`
+	}
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		t.Fatal(err)
+	}
+	schemas := doc.Components.Schemas
+	var source properties
+	for _, branch := range schemas.Source.OneOf {
+		if branch.Properties != nil {
+			source = branch
+		}
+	}
+
+	tests := []struct {
+		name   string
+		typ    reflect.Type
+		schema properties
+	}{
+		{"ScrapeRequest", reflect.TypeOf(ScrapeRequest{}), schemas.ScrapeRequest},
+		{"Source", reflect.TypeOf(Source{}), source},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var fields []string
+			for i := 0; i < tt.typ.NumField(); i++ {
+				name, _, _ := strings.Cut(tt.typ.Field(i).Tag.Get("json"), ",")
+				if name != "-" {
+					fields = append(fields, name)
+				}
+			}
+			for _, name := range fields {
+				if _, ok := tt.schema.Properties[name]; !ok {
+					t.Errorf("field %s is not in the %s schema", name, tt.name)
+				}
+			}
+			for name := range tt.schema.Properties {
+				if !slices.Contains(fields, name) {
+					t.Errorf("property %s of the %s schema has no field", name, tt.name)
+				}
+			}
+		})
+	}
+}
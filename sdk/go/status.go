@@ -0,0 +1,64 @@
+package scrapeapi
+
+// Status is the lifecycle state of a scraping job
+type Status string
+
+// Job statuses reported by the API
+const (
+	StatusQueued    Status = "queued"
+	StatusRunning   Status = "running"
+	StatusCompleted Status = "completed"
+	StatusFailed    Status = "failed"
+	StatusCanceled  Status = "canceled"
+)
+
+// IsTerminal reports whether the job has finished and will not change status again
+func (s Status) IsTerminal() bool {
+	return s == StatusCompleted || s == StatusFailed || s == StatusCanceled
+}
+
+// IsPending reports whether the job is still waiting or in progress
+func (s Status) IsPending() bool {
+	return s == StatusQueued || s == StatusRunning
+}
+
+// IsValid reports whether s is one of the known statuses
+func (s Status) IsValid() bool {
+	return s.IsTerminal() || s.IsPending()
+}
+
+func (s Status) String() string {
+	return string(s)
+}
+
+// Graph selects which scraping pipeline the API runs
+type Graph string
+
+// Graph types supported by the API
+const (
+	// GraphSmart scrapes a single URL or HTML document
+	GraphSmart Graph = "smart"
+	// GraphMulti scrapes several URLs given in Sources
+	GraphMulti Graph = "multi"
+	// GraphSearch runs a web search and scrapes the results
+	GraphSearch Graph = "search"
+	// GraphSitemap scrapes the pages listed in the sitemap at WebsiteURL
+	GraphSitemap Graph = "sitemap"
+	// GraphCrawl follows links from WebsiteURL and scrapes every page it reaches
+	GraphCrawl Graph = "crawl"
+	// GraphFeed reads the RSS or Atom feed at WebsiteURL
+	GraphFeed Graph = "feed"
+)
+
+// IsValid reports whether g is one of the known graph types
+func (g Graph) IsValid() bool {
+	switch g {
+	case GraphSmart, GraphMulti, GraphSearch, GraphSitemap, GraphCrawl, GraphFeed:
+		return true
+	}
+	return false
+}
+
+func (g Graph) String() string {
+	return string(g)
+}
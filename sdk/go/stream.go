@@ -0,0 +1,149 @@
+package scrapeapi
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/coder/websocket"
+	"github.com/coder/websocket/wsjson"
+)
+
+// StreamMessageType tells what a StreamMessage carries
+type StreamMessageType string
+
+// Stream message types sent by the server
+const (
+	// StreamChunk carries a batch of items extracted so far
+	StreamChunk StreamMessageType = "chunk"
+	// StreamComplete carries the final job response; no messages follow it
+	StreamComplete StreamMessageType = "complete"
+	// StreamError reports that the job failed; no messages follow it
+	StreamError StreamMessageType = "error"
+)
+
+// DecodeData decodes the partial result of a chunk into the value pointed to by into
+func (m *StreamMessage) DecodeData(into interface{}) error {
+	if len(m.Data) == 0 {
+		return ErrNoResult
+	}
+	if err := json.Unmarshal(m.Data, into); err != nil {
+		return fmt.Errorf("decode chunk %d: %w", m.Sequence, err)
+	}
+	return nil
+}
+
+// Stream is an open streaming scrape. Call Recv until it returns io.EOF.
+type Stream struct {
+	conn *websocket.Conn
+	// ctx is the caller's context, canceled early by Close
+	ctx     context.Context
+	cancel  context.CancelFunc
+	lastSeq int
+	done    bool
+}
+
+// StreamScrape starts a job over a WebSocket connection and streams partial
+// results as the server extracts them, followed by a final completion message.
+// Recv stops waiting once ctx ends, so ctx must outlive the stream.
+func (c *Client) StreamScrape(ctx context.Context, req *ScrapeRequest, opts ...RequestOption) (*Stream, error) {
+	// Create a span for this operation
+	// If there's no existing span in context, this creates a new root span
+	ctx, span := c.tracer.Start(ctx, "scrapeapi.StreamScrape")
+	defer span.End()
+
+	req, err := c.resolveLLM(req)
+	if err != nil {
+		return nil, err
+	}
+	span.SetAttributes(requestAttributes(req)...)
+	if err := req.Validate(); err != nil {
+		return nil, err
+	}
+	if err := c.checkRobots(ctx, req); err != nil {
+		return nil, err
+	}
+	req = c.withTraceContext(ctx, req)
+
+	header := http.Header{}
+	c.setHeaders(ctx, header, opts...)
+
+	conn, resp, err := websocket.Dial(ctx, websocketURL(c.BaseURL)+"/v1/scrape/stream", &websocket.DialOptions{
+		HTTPClient: c.longLivedClient(),
+		HTTPHeader: header,
+	})
+	if err != nil {
+		if resp != nil && (resp.StatusCode < 200 || resp.StatusCode > 299) {
+			return nil, newAPIError(resp)
+		}
+		return nil, fmt.Errorf("dial stream: %w", err)
+	}
+	// Results can be large; the default 32KiB read limit is too small
+	conn.SetReadLimit(64 << 20)
+
+	if err := wsjson.Write(ctx, conn, req); err != nil {
+		conn.Close(websocket.StatusInternalError, "send request")
+		return nil, fmt.Errorf("send request: %w", err)
+	}
+
+	streamCtx, cancel := context.WithCancel(ctx)
+	return &Stream{conn: conn, ctx: streamCtx, cancel: cancel}, nil
+}
+
+// Recv returns the next message. After the StreamComplete or StreamError
+// message has been returned, Recv returns io.EOF.
+func (s *Stream) Recv() (*StreamMessage, error) {
+	if s.done {
+		return nil, io.EOF
+	}
+
+	var msg StreamMessage
+	if err := wsjson.Read(s.ctx, s.conn, &msg); err != nil {
+		s.done = true
+		s.cancel()
+		if websocket.CloseStatus(err) == websocket.StatusNormalClosure {
+			return nil, io.ErrUnexpectedEOF
+		}
+		return nil, fmt.Errorf("read stream: %w", err)
+	}
+
+	if msg.Sequence != s.lastSeq+1 {
+		s.done = true
+		s.conn.Close(websocket.StatusProtocolError, "sequence gap")
+		s.cancel()
+		return nil, fmt.Errorf("stream message out of order: got sequence %d after %d", msg.Sequence, s.lastSeq)
+	}
+	s.lastSeq = msg.Sequence
+
+	if msg.Type == StreamComplete || msg.Type == StreamError {
+		s.done = true
+		s.conn.Close(websocket.StatusNormalClosure, "")
+		s.cancel()
+	}
+
+	return &msg, nil
+}
+
+// Close ends the stream. It is safe to call after Recv returned io.EOF.
+func (s *Stream) Close() error {
+	if s.done {
+		return nil
+	}
+	s.done = true
+	defer s.cancel()
+	return s.conn.Close(websocket.StatusNormalClosure, "")
+}
+
+// websocketURL turns an http(s) base URL into a ws(s) one
+func websocketURL(baseURL string) string {
+	switch {
+	case strings.HasPrefix(baseURL, "https://"):
+		return "wss://" + strings.TrimPrefix(baseURL, "https://")
+	case strings.HasPrefix(baseURL, "http://"):
+		return "ws://" + strings.TrimPrefix(baseURL, "http://")
+	}
+	return baseURL
+}
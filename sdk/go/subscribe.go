@@ -0,0 +1,169 @@
+package scrapeapi
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// ErrStreamEnded is reported by Subscription.Err when the server closes the
+// stream before the job reaches a terminal status
+var ErrStreamEnded = errors.New("event stream ended before job finished")
+
+// Subscription delivers status updates of a job streamed by the server as Server-Sent Events
+type Subscription struct {
+	events chan ScrapeResponse
+	cancel context.CancelFunc
+	err    error
+}
+
+// Events returns the channel of status updates. It is closed once the job
+// reaches a terminal status, the stream fails or the subscription is closed.
+func (s *Subscription) Events() <-chan ScrapeResponse {
+	return s.events
+}
+
+// Err returns the error that ended the stream, if any.
+// It is only valid after the Events channel is closed.
+func (s *Subscription) Err() error {
+	return s.err
+}
+
+// Close stops the subscription and releases the underlying connection
+func (s *Subscription) Close() {
+	s.cancel()
+}
+
+// SubscribeScrape opens a Server-Sent Events stream of status updates for a job.
+// Servers without streaming support respond with an *APIError; see IsStreamingUnsupported.
+func (c *Client) SubscribeScrape(ctx context.Context, requestID string, opts ...RequestOption) (*Subscription, error) {
+	// Create a span for this operation
+	// If there's no existing span in context, this creates a new root span
+	ctx, span := c.tracer.Start(ctx, "scrapeapi.SubscribeScrape")
+
+	ctx, cancel := context.WithCancel(ctx)
+
+	httpReq, err := c.newRequest(ctx, http.MethodGet, "/v1/scrape/"+url.PathEscape(requestID)+"/events", nil, opts...)
+	if err != nil {
+		cancel()
+		span.End()
+		return nil, err
+	}
+	httpReq.Header.Set("Accept", "text/event-stream")
+	httpReq.Header.Set("Cache-Control", "no-cache")
+
+	resp, err := c.send(c.longLivedClient(), httpReq)
+	if err != nil {
+		cancel()
+		span.End()
+		return nil, err
+	}
+
+	if ct := resp.Header.Get("Content-Type"); !strings.HasPrefix(ct, "text/event-stream") {
+		resp.Body.Close()
+		cancel()
+		span.End()
+		return nil, fmt.Errorf("unexpected content type %q for event stream", ct)
+	}
+
+	sub := &Subscription{
+		events: make(chan ScrapeResponse),
+		cancel: cancel,
+	}
+
+	go func() {
+		defer span.End()
+		defer close(sub.events)
+		defer resp.Body.Close()
+		sub.err = c.readEvents(ctx, resp.Body, sub.events)
+	}()
+
+	return sub, nil
+}
+
+// IsStreamingUnsupported reports whether err from SubscribeScrape means the
+// server has no event stream endpoint, so callers should fall back to polling
+func IsStreamingUnsupported(err error) bool {
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) {
+		return false
+	}
+	switch apiErr.StatusCode {
+	case http.StatusNotFound, http.StatusMethodNotAllowed, http.StatusNotAcceptable, http.StatusNotImplemented:
+		return true
+	}
+	return false
+}
+
+// readEvents parses the SSE stream in r and sends every decoded status update to events
+func (c *Client) readEvents(ctx context.Context, r io.Reader, events chan<- ScrapeResponse) error {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 16<<20)
+
+	var eventType string
+	var data strings.Builder
+	for scanner.Scan() {
+		line := scanner.Text()
+
+		switch {
+		case line == "":
+			// A blank line dispatches the buffered event
+			if data.Len() == 0 {
+				eventType = ""
+				continue
+			}
+			payload := data.String()
+			data.Reset()
+
+			typ := eventType
+			eventType = ""
+			if typ != "" && typ != "message" && typ != "status" {
+				c.logger.DebugContext(ctx, "ignoring event", "type", typ)
+				continue
+			}
+
+			var update ScrapeResponse
+			if err := json.Unmarshal([]byte(payload), &update); err != nil {
+				return fmt.Errorf("decode event: %w", err)
+			}
+
+			select {
+			case events <- update:
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+
+			if update.Status.IsTerminal() {
+				return nil
+			}
+		case strings.HasPrefix(line, ":"):
+			// Comment, used by servers as keep-alive
+		default:
+			field, value, _ := strings.Cut(line, ":")
+			value = strings.TrimPrefix(value, " ")
+			switch field {
+			case "event":
+				eventType = value
+			case "data":
+				if data.Len() > 0 {
+					data.WriteByte('\n')
+				}
+				data.WriteString(value)
+			}
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		return fmt.Errorf("read event stream: %w", err)
+	}
+	return ErrStreamEnded
+}
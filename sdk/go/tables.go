@@ -0,0 +1,37 @@
+package scrapeapi
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+)
+
+// WriteCSV writes the table to w as CSV, headers first
+func (t *Table) WriteCSV(w io.Writer) error {
+	cw := csv.NewWriter(w)
+	if len(t.Headers) > 0 {
+		if err := cw.Write(t.Headers); err != nil {
+			return fmt.Errorf("write csv: %w", err)
+		}
+	}
+	if err := cw.WriteAll(t.Rows); err != nil {
+		return fmt.Errorf("write csv: %w", err)
+	}
+	return nil
+}
+
+// TablesToCSV writes every table of the response to w as CSV,
+// separating consecutive tables with an empty line
+func (r *ScrapeResponse) TablesToCSV(w io.Writer) error {
+	for i := range r.Tables {
+		if i > 0 {
+			if _, err := io.WriteString(w, "\n"); err != nil {
+				return fmt.Errorf("write csv: %w", err)
+			}
+		}
+		if err := r.Tables[i].WriteCSV(w); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,91 @@
+package scrapeapi
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+)
+
+// ScrapeAndWaitTyped starts a scrape job, waits for completion and decodes the result into T.
+// If req.OutputSchema is nil, a JSON Schema is generated from T.
+// The raw response is returned alongside the decoded value.
+//
+//	jobs, resp, err := scrapeapi.ScrapeAndWaitTyped[ParsedJobsResponse](ctx, client, req)
+func ScrapeAndWaitTyped[T any](ctx context.Context, c ScrapeClient, req *ScrapeRequest, opts ...WaitOption) (T, *ScrapeResponse, error) {
+	var zero T
+
+	if req.OutputSchema == nil {
+		typedReq := *req
+		typedReq.OutputSchema = SchemaFor[T]()
+		req = &typedReq
+	}
+
+	resp, err := c.ScrapeAndWait(ctx, req, opts...)
+	if err != nil {
+		return zero, resp, err
+	}
+
+	var out T
+	if err := resp.DecodeResult(&out); err != nil {
+		return zero, resp, err
+	}
+
+	return out, resp, nil
+}
+
+// ResultData returns the job result without the {"data": ..., "schema_validation": ...}
+// envelope the API puts around completed results
+func (r *ScrapeResponse) ResultData() interface{} {
+	return resultData(r.Result)
+}
+
+// resultData unwraps the {"data": ..., "schema_validation": ...} envelope
+// the API puts around completed results
+func resultData(result interface{}) interface{} {
+	m, ok := result.(map[string]interface{})
+	if !ok {
+		return result
+	}
+	data, hasData := m["data"]
+	_, hasValidation := m["schema_validation"]
+	if hasData && hasValidation {
+		return data
+	}
+	return result
+}
+
+// ErrNoResult is returned by DecodeResult when the response carries no result
+var ErrNoResult = errors.New("response has no result")
+
+// DecodeResult decodes the job result into the value pointed to by into.
+// Unlike a plain json round-trip, fields in the result that into has no place for
+// and values of the wrong type are reported as errors naming the offending field.
+func (r *ScrapeResponse) DecodeResult(into interface{}) error {
+	return decodeResult(resultData(r.Result), into)
+}
+
+// decodeResult strictly decodes a generic JSON value into the value pointed to by into
+func decodeResult(data interface{}, into interface{}) error {
+	if data == nil {
+		return ErrNoResult
+	}
+
+	raw, err := json.Marshal(data)
+	if err != nil {
+		return fmt.Errorf("decode result: %w", err)
+	}
+
+	dec := json.NewDecoder(bytes.NewReader(raw))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(into); err != nil {
+		var typeErr *json.UnmarshalTypeError
+		if errors.As(err, &typeErr) && typeErr.Field != "" {
+			return fmt.Errorf("decode result: field %q: %w", typeErr.Field, err)
+		}
+		return fmt.Errorf("decode result: %w", err)
+	}
+
+	return nil
+}
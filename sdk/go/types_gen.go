@@ -0,0 +1,181 @@
+// Code generated by openapigen from openapi.yaml; DO NOT EDIT.
+
+package scrapeapi
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// ScrapeResponse represents the API response
+type ScrapeResponse struct {
+	RequestID  string   `json:"request_id"`
+	Status     Status   `json:"status"`
+	Graph      Graph    `json:"graph"`
+	UserPrompt string   `json:"user_prompt"`
+	WebsiteURL *string  `json:"website_url,omitempty"`
+	Sources    []Source `json:"sources,omitempty"`
+	// The extracted data, shaped by the output schema
+	Result            interface{}           `json:"result,omitempty"`
+	Error             string                `json:"error,omitempty"`
+	ErrorCode         JobErrorCode          `json:"error_code,omitempty"`
+	Tags              []string              `json:"tags,omitempty"`
+	CreatedAt         *time.Time            `json:"created_at,omitempty"`
+	Progress          *Progress             `json:"progress,omitempty"`
+	Artifacts         []Artifact            `json:"artifacts,omitempty"`
+	Pages             map[string]PageResult `json:"pages,omitempty"`
+	Content           string                `json:"content,omitempty"`
+	Entries           []FeedEntry           `json:"entries,omitempty"`
+	Images            []ImageInfo           `json:"images,omitempty"`
+	Tables            []Table               `json:"tables,omitempty"`
+	DuplicatesRemoved int                   `json:"duplicates_removed,omitempty"`
+	CacheHit          bool                  `json:"cache_hit,omitempty"`
+	Model             string                `json:"model,omitempty"`
+	Attributions      []Attribution         `json:"attributions,omitempty"`
+	Usage             *UsageInfo            `json:"usage,omitempty"`
+	// TraceID identifies the server span that runs the job; see ServerSpanContext
+	TraceID string `json:"trace_id,omitempty"`
+	// SpanID identifies the server span that runs the job; see ServerSpanContext
+	SpanID string `json:"span_id,omitempty"`
+	// TraceFlags are the W3C trace flags of the server span as two hex digits,
+	// e.g. "01" if the server sampled it; see ServerSpanContext
+	TraceFlags string `json:"trace_flags,omitempty"`
+}
+
+// UsageInfo is the LLM usage a job was billed for, summed over every page and
+// retry. It is reported once the job has finished.
+type UsageInfo struct {
+	PromptTokens     int     `json:"prompt_tokens"`
+	CompletionTokens int     `json:"completion_tokens"`
+	CostUSD          float64 `json:"cost_usd"`
+	// Model is the model most of the tokens were spent on; see
+	// ScrapeResponse.Model
+	Model string `json:"model,omitempty"`
+}
+
+// Progress is the server-side progress of a job
+type Progress struct {
+	Stage Stage `json:"stage,omitempty"`
+	// Percent is the overall completion from 0 to 100
+	Percent    float64 `json:"percent,omitempty"`
+	PagesDone  int     `json:"pages_done,omitempty"`
+	PagesTotal int     `json:"pages_total,omitempty"`
+}
+
+// Artifact describes a file captured during a job
+type Artifact struct {
+	ID          string       `json:"id"`
+	RequestID   string       `json:"request_id"`
+	Type        ArtifactType `json:"type"`
+	ContentType string       `json:"content_type"`
+	Size        int64        `json:"size"`
+	CreatedAt   time.Time    `json:"created_at"`
+}
+
+// PageResult is the outcome of one page of a job that scrapes many pages, such
+// as the sitemap and crawl graphs
+type PageResult struct {
+	URL    string      `json:"url"`
+	Status Status      `json:"status"`
+	Result interface{} `json:"result,omitempty"`
+	// Content is the page as markdown or text for jobs with such an OutputFormat
+	Content string `json:"content,omitempty"`
+	Error   string `json:"error,omitempty"`
+	// Depth is the number of link hops from the start URL of a crawl
+	Depth int `json:"depth,omitempty"`
+}
+
+// FeedEntry is one item of an RSS or Atom feed returned by the feed graph
+type FeedEntry struct {
+	ID        string     `json:"id,omitempty"`
+	Title     string     `json:"title"`
+	Link      string     `json:"link"`
+	Published *time.Time `json:"published,omitempty"`
+	Summary   string     `json:"summary,omitempty"`
+	Author    string     `json:"author,omitempty"`
+	// Status, Result and Error describe the scrape of Link when
+	// FeedConfig.ScrapeEntries is set
+	Status Status      `json:"status,omitempty"`
+	Result interface{} `json:"result,omitempty"`
+	Error  string      `json:"error,omitempty"`
+}
+
+// ImageInfo is an image found on a scraped page
+type ImageInfo struct {
+	URL    string `json:"url"`
+	Alt    string `json:"alt,omitempty"`
+	Width  int    `json:"width,omitempty"`
+	Height int    `json:"height,omitempty"`
+	// Text is what OCR read from the image, for requests with OCR set
+	Text string `json:"text,omitempty"`
+}
+
+// Table is an HTML table returned verbatim by OutputTables jobs, without
+// passing through the LLM, so large numeric tables keep every digit
+type Table struct {
+	Caption string     `json:"caption,omitempty"`
+	Headers []string   `json:"headers,omitempty"`
+	Rows    [][]string `json:"rows"`
+}
+
+// Attribution records where on which page an extracted item was found
+type Attribution struct {
+	// Path locates the item in the result, e.g. "jobs[3]"
+	Path string `json:"path"`
+	// SourceURL is the page the item was extracted from
+	SourceURL string `json:"source_url"`
+	// XPath is the page region the item came from, if the server could pin it down
+	XPath string `json:"xpath,omitempty"`
+}
+
+// ScrapeList is one page of ListScrapes results
+type ScrapeList struct {
+	Items []ScrapeResponse `json:"items"`
+	// NextCursor fetches the next page; empty on the last page
+	NextCursor string `json:"next_cursor,omitempty"`
+}
+
+// BatchJob is the job started for one URL of a batch
+type BatchJob struct {
+	URL       string `json:"url"`
+	RequestID string `json:"request_id,omitempty"`
+	// Error explains why no job was started for URL
+	Error string `json:"error,omitempty"`
+}
+
+// BatchScrapeResponse lists the jobs started by StartBatchScrape, in the order
+// of the submitted URLs
+type BatchScrapeResponse struct {
+	BatchID string     `json:"batch_id"`
+	Jobs    []BatchJob `json:"jobs"`
+}
+
+// LogEntry is one server-side log line of a job
+type LogEntry struct {
+	// Sequence orders entries within a job; pass it as LogOptions.After to resume
+	Sequence  int64                  `json:"seq"`
+	Timestamp time.Time              `json:"timestamp"`
+	Level     LogLevel               `json:"level"`
+	Message   string                 `json:"message"`
+	Fields    map[string]interface{} `json:"fields,omitempty"`
+}
+
+// ScrapeLogs is a batch of job log entries
+type ScrapeLogs struct {
+	Entries []LogEntry `json:"entries"`
+	// Status is the job status at the time the logs were read
+	Status Status `json:"status"`
+}
+
+// StreamMessage is one message of a streaming scrape
+type StreamMessage struct {
+	Type      StreamMessageType `json:"type"`
+	RequestID string            `json:"request_id"`
+	// Sequence numbers messages from 1 without gaps
+	Sequence int `json:"sequence"`
+	// Data holds the partial result of a StreamChunk
+	Data json.RawMessage `json:"data,omitempty"`
+	// Response holds the final job state of a StreamComplete or StreamError
+	Response *ScrapeResponse `json:"response,omitempty"`
+	Error    string          `json:"error,omitempty"`
+}
@@ -0,0 +1,6 @@
+package scrapeapi
+
+// TotalTokens returns the sum of prompt and completion tokens
+func (u *UsageInfo) TotalTokens() int {
+	return u.PromptTokens + u.CompletionTokens
+}
@@ -0,0 +1,383 @@
+package scrapeapi
+
+import (
+	"fmt"
+	"net/url"
+	"slices"
+	"strings"
+	"unicode"
+)
+
+// FieldError describes one problem with a request field
+type FieldError struct {
+	// Field is the JSON name of the offending field, e.g. "website_url" or "sources[2]"
+	Field   string
+	Message string
+}
+
+func (e *FieldError) Error() string {
+	return e.Field + ": " + e.Message
+}
+
+// ValidationError lists every problem Validate found in a request
+type ValidationError struct {
+	Errors []*FieldError
+}
+
+func (e *ValidationError) Error() string {
+	msgs := make([]string, len(e.Errors))
+	for i, fe := range e.Errors {
+		msgs[i] = fe.Error()
+	}
+	return "invalid request: " + strings.Join(msgs, "; ")
+}
+
+// Unwrap exposes the individual field errors to errors.Is and errors.As
+func (e *ValidationError) Unwrap() []error {
+	errs := make([]error, len(e.Errors))
+	for i, fe := range e.Errors {
+		errs[i] = fe
+	}
+	return errs
+}
+
+// validator collects field errors
+type validator struct {
+	errs []*FieldError
+}
+
+func (v *validator) addf(field, format string, args ...interface{}) {
+	v.errs = append(v.errs, &FieldError{Field: field, Message: fmt.Sprintf(format, args...)})
+}
+
+func (v *validator) err() error {
+	if len(v.errs) == 0 {
+		return nil
+	}
+	return &ValidationError{Errors: v.errs}
+}
+
+// checkURL reports a field error unless raw is an absolute http(s) URL
+func (v *validator) checkURL(field, raw string) {
+	u, err := url.Parse(raw)
+	switch {
+	case err != nil:
+		v.addf(field, "invalid URL: %v", err)
+	case u.Scheme != "http" && u.Scheme != "https":
+		v.addf(field, "URL must use http or https, got %q", raw)
+	case u.Host == "":
+		v.addf(field, "URL has no host: %q", raw)
+	}
+}
+
+// Validate checks the request for mistakes the server would reject,
+// reporting all of them at once as a *ValidationError.
+// StartScrape calls it before sending the request.
+func (r *ScrapeRequest) Validate() error {
+	v := &validator{}
+	r.validate(v)
+	return v.err()
+}
+
+func (r *ScrapeRequest) validate(v *validator) {
+	r.validateSource(v)
+	r.validateOptions(v)
+}
+
+// validateSource checks the graph and where its input comes from
+func (r *ScrapeRequest) validateSource(v *validator) {
+	switch {
+	case r.Graph == "":
+		v.addf("graph", "is required")
+	case !r.Graph.IsValid():
+		v.addf("graph", "unknown graph %q", r.Graph)
+	}
+
+	if r.WebsiteURL != nil && r.WebsiteHTML != nil {
+		v.addf("website_html", "is mutually exclusive with website_url")
+	}
+	if r.WebsiteURL != nil {
+		v.checkURL("website_url", *r.WebsiteURL)
+	}
+	if r.WebsiteHTML != nil && strings.TrimSpace(*r.WebsiteHTML) == "" {
+		v.addf("website_html", "must not be empty")
+	}
+	if r.Document != nil {
+		if r.WebsiteURL != nil || r.WebsiteHTML != nil {
+			v.addf("document", "is mutually exclusive with website_url and website_html")
+		}
+		if r.Graph != "" && r.Graph != GraphSmart {
+			v.addf("document", "is only used by the smart graph")
+		}
+		r.Document.validate(v, "document")
+	}
+	for i := range r.Sources {
+		r.Sources[i].validate(v, fmt.Sprintf("sources[%d]", i))
+	}
+	if r.Merge != nil {
+		if len(r.Sources) < 2 {
+			v.addf("merge", "needs at least two sources")
+		}
+		r.Merge.validate(v, "merge")
+	}
+
+	switch r.Graph {
+	case GraphSmart:
+		if r.WebsiteURL == nil && r.WebsiteHTML == nil && r.Document == nil && len(r.Sources) == 0 {
+			v.addf("website_url", "smart graph requires website_url, website_html, document or sources")
+		}
+	case GraphMulti:
+		if len(r.Sources) == 0 {
+			v.addf("sources", "multi graph requires sources")
+		}
+	case GraphSearch:
+		if r.SearchQuery == nil || strings.TrimSpace(*r.SearchQuery) == "" {
+			v.addf("search_query", "search graph requires search_query")
+		}
+	case GraphSitemap:
+		if r.WebsiteURL == nil {
+			v.addf("website_url", "sitemap graph requires the sitemap URL in website_url")
+		}
+	case GraphCrawl:
+		if r.WebsiteURL == nil {
+			v.addf("website_url", "crawl graph requires the start URL in website_url")
+		}
+	case GraphFeed:
+		if r.WebsiteURL == nil {
+			v.addf("website_url", "feed graph requires the feed URL in website_url")
+		}
+	}
+
+	if r.Sitemap != nil {
+		if r.Graph != GraphSitemap {
+			v.addf("sitemap", "is only used by the sitemap graph")
+		}
+		r.Sitemap.validate(v, "sitemap")
+	}
+	if r.Crawl != nil {
+		if r.Graph != GraphCrawl {
+			v.addf("crawl", "is only used by the crawl graph")
+		}
+		r.Crawl.validate(v, "crawl")
+	}
+	if r.Feed != nil {
+		if r.Graph != GraphFeed {
+			v.addf("feed", "is only used by the feed graph")
+		}
+		r.Feed.validate(v, "feed")
+	}
+	if r.CrawlRateLimit != nil {
+		if r.Graph != GraphCrawl && r.Graph != GraphSitemap {
+			v.addf("crawl_rate_limit", "is only used by the crawl and sitemap graphs")
+		}
+		r.CrawlRateLimit.validate(v, "crawl_rate_limit")
+	}
+
+	if r.SearchQuery != nil && r.Graph != GraphSearch {
+		v.addf("search_query", "is only used by the search graph")
+	}
+	if r.Search != nil {
+		if r.Graph != GraphSearch {
+			v.addf("search", "is only used by the search graph")
+		}
+		r.Search.validate(v, "search")
+	}
+}
+
+// extracts reports whether the job runs LLM extraction and so needs a prompt
+func (r *ScrapeRequest) extracts() bool {
+	if r.OutputFormat.isContent() {
+		return false
+	}
+	if r.Graph == GraphFeed {
+		return r.Feed != nil && r.Feed.ScrapeEntries
+	}
+	return true
+}
+
+// validateOptions checks the settings that do not depend on the source
+func (r *ScrapeRequest) validateOptions(v *validator) {
+	if strings.TrimSpace(r.UserPrompt) == "" && r.extracts() {
+		v.addf("user_prompt", "is required")
+	}
+	if r.OutputFormat != "" && !r.OutputFormat.IsValid() {
+		v.addf("output_format", "unknown output format %q", r.OutputFormat)
+	}
+	if r.OutputFormat.isContent() && r.OutputSchema != nil {
+		v.addf("output_schema", "is not used with %s output", r.OutputFormat)
+	}
+	if r.OutputLanguage != "" && !validLocale(r.OutputLanguage) {
+		v.addf("output_language", "must be a BCP 47 language tag such as \"en\", got %q", r.OutputLanguage)
+	}
+	if r.MaxResults != nil && *r.MaxResults <= 0 {
+		v.addf("max_results", "must be positive, got %d", *r.MaxResults)
+	}
+	if r.TimeoutSec < 0 {
+		v.addf("timeout_sec", "must not be negative, got %d", r.TimeoutSec)
+	}
+	if r.MaxCostUSD != nil && *r.MaxCostUSD <= 0 {
+		v.addf("max_cost_usd", "must be positive, got %g", *r.MaxCostUSD)
+	}
+	if r.MaxTokens != nil && *r.MaxTokens <= 0 {
+		v.addf("max_tokens", "must be positive, got %d", *r.MaxTokens)
+	}
+	if r.CallbackURL != nil {
+		v.checkURL("callback_url", *r.CallbackURL)
+	}
+	if r.Cache != nil {
+		r.Cache.validate(v, "cache")
+	}
+
+	for name := range r.Headers {
+		if !validHeaderName(name) {
+			v.addf("headers", "invalid header name %q", name)
+		}
+	}
+	for i, c := range r.Cookies {
+		if c.Name == "" {
+			v.addf(fmt.Sprintf("cookies[%d].name", i), "is required")
+		}
+	}
+	if r.Proxy != nil {
+		r.Proxy.validate(v)
+	}
+	if r.Captcha != nil {
+		r.Captcha.validate(v, "captcha")
+	}
+	if r.Auth != nil {
+		r.Auth.validate(v, "auth")
+		if r.Headers["Authorization"] != "" && (r.Auth.Basic != nil || r.Auth.BearerToken != "") {
+			v.addf("auth", "conflicts with the Authorization header")
+		}
+	}
+	validateResources(v, "block_resources", r.BlockResources)
+	if r.CaptureScreenshot && slices.Contains(r.BlockResources, ResourceCSS) {
+		v.addf("block_resources", "blocking css would break the screenshot")
+	}
+	validateActions(v, "actions", r.Actions)
+	if r.SubmitForm != nil {
+		r.SubmitForm.validate(v, "submit_form")
+	}
+	if r.WaitFor != nil {
+		r.WaitFor.validate(v, "wait_for")
+	}
+	if r.Device != nil {
+		r.Device.validate(v, "device")
+	}
+	if r.Locale != "" && !validLocale(r.Locale) {
+		v.addf("locale", "must be a BCP 47 language tag such as \"en-US\", got %q", r.Locale)
+	}
+	if r.Timezone != "" && !validTimezone(r.Timezone) {
+		v.addf("timezone", "must be an IANA time zone such as \"Europe/Berlin\", got %q", r.Timezone)
+	}
+	if r.Geo != nil {
+		r.Geo.validate(v, "geo")
+		if r.Headers["Accept-Language"] != "" && r.Geo.AcceptLanguage != "" {
+			v.addf("geo.accept_language", "conflicts with the Accept-Language header")
+		}
+	}
+	if r.Pagination != nil {
+		r.Pagination.validate(v, "pagination")
+		if r.WebsiteHTML != nil {
+			v.addf("pagination", "cannot follow pages of website_html")
+		}
+	}
+	if r.Dedupe != nil {
+		if r.OutputFormat.isContent() {
+			v.addf("dedupe", "is not used with %s output", r.OutputFormat)
+		}
+		r.Dedupe.validate(v, "dedupe")
+	}
+	if r.ScreenshotFullPage && !r.CaptureScreenshot {
+		v.addf("screenshot_full_page", "requires capture_screenshot")
+	}
+
+	if r.LLM != nil {
+		if r.LLMAlias != "" {
+			v.addf("llm_alias", "cannot be combined with llm")
+		}
+		r.LLM.validate(v, "llm")
+	}
+	if r.Embeddings != nil {
+		r.Embeddings.validate(v, "embeddings")
+	}
+}
+
+// Validate checks the batch for mistakes the server would reject,
+// reporting all of them at once as a *ValidationError.
+// StartBatchScrape calls it before sending the request.
+func (r *BatchScrapeRequest) Validate() error {
+	v := &validator{}
+
+	if len(r.URLs) == 0 {
+		v.addf("urls", "is required")
+	}
+	for i, u := range r.URLs {
+		v.checkURL(fmt.Sprintf("urls[%d]", i), u)
+	}
+	if r.Graph != "" && r.Graph != GraphSmart {
+		v.addf("graph", "batches only support the smart graph, got %q", r.Graph)
+	}
+	// The URLs are the only source of a batch
+	if r.WebsiteURL != nil {
+		v.addf("website_url", "is not used by batches; list the URLs in urls")
+	}
+	if r.WebsiteHTML != nil {
+		v.addf("website_html", "is not used by batches")
+	}
+	if len(r.Sources) > 0 {
+		v.addf("sources", "is not used by batches")
+	}
+	if r.SearchQuery != nil {
+		v.addf("search_query", "is not used by batches")
+	}
+	r.validateOptions(v)
+
+	return v.err()
+}
+
+// validHeaderName reports whether name is a valid HTTP header field name (an RFC 7230 token)
+func validHeaderName(name string) bool {
+	if name == "" {
+		return false
+	}
+	for _, r := range name {
+		if r > unicode.MaxASCII || r <= ' ' || strings.ContainsRune("\"(),/:;<=>?@[\\]{}", r) {
+			return false
+		}
+	}
+	return true
+}
+
+func (p *ProxyConfig) validate(v *validator) {
+	switch p.Type {
+	case "", ProxyDatacenter, ProxyResidential:
+	default:
+		v.addf("proxy.type", "unknown proxy type %q", p.Type)
+	}
+	if p.URL != "" {
+		if p.Type != "" || p.Country != "" || p.SessionID != "" {
+			v.addf("proxy.url", "is mutually exclusive with type, country and session_id")
+		}
+		if u, err := url.Parse(p.URL); err != nil || u.Host == "" {
+			v.addf("proxy.url", "invalid proxy URL")
+		} else if u.Scheme != "http" && u.Scheme != "https" && u.Scheme != "socks5" {
+			v.addf("proxy.url", "proxy URL must use http, https or socks5, got %q", u.Scheme)
+		}
+	}
+	v.checkCountry("proxy.country", p.Country)
+}
+
+// checkCountry reports a field error unless code is empty or an ISO 3166-1 alpha-2 code
+func (v *validator) checkCountry(field, code string) {
+	if code == "" {
+		return
+	}
+	if len(code) != 2 || !isASCIILetter(code[0]) || !isASCIILetter(code[1]) {
+		v.addf(field, "must be a two-letter ISO 3166-1 country code, got %q", code)
+	}
+}
+
+func isASCIILetter(b byte) bool {
+	return (b >= 'a' && b <= 'z') || (b >= 'A' && b <= 'Z')
+}
@@ -0,0 +1,170 @@
+// Package webhook receives ScrapeAPI job callbacks.
+//
+// When a ScrapeRequest carries a CallbackURL, the server POSTs the job's
+// ScrapeResponse to that URL on completion. Each delivery is signed with
+// HMAC-SHA256 over "<timestamp>.<body>" using the shared webhook secret.
+package webhook
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	scrapeapi "github.com/dir01/scrapeapi/sdk/go"
+)
+
+// Headers set by the server on every delivery
+const (
+	SignatureHeader = "X-ScrapeAPI-Signature"
+	TimestampHeader = "X-ScrapeAPI-Timestamp"
+)
+
+// signaturePrefix precedes the hex-encoded HMAC in SignatureHeader
+const signaturePrefix = "sha256="
+
+// DefaultTolerance is how old a delivery may be before it is rejected as a replay
+const DefaultTolerance = 5 * time.Minute
+
+// maxBodySize caps the size of a delivery body
+const maxBodySize = 10 << 20
+
+// Verification errors
+var (
+	ErrMissingSignature = errors.New("missing signature or timestamp header")
+	ErrInvalidSignature = errors.New("invalid signature")
+	ErrInvalidTimestamp = errors.New("invalid timestamp")
+	ErrExpired          = errors.New("timestamp outside tolerance")
+)
+
+// HandlerFunc is called with every verified job event.
+// Returning an error responds with 500 so the server retries the delivery.
+type HandlerFunc func(ctx context.Context, event *scrapeapi.ScrapeResponse) error
+
+// Option is a functional option for configuring a Handler
+type Option func(*Handler)
+
+// WithTolerance sets how far a delivery timestamp may drift from the local clock (default: 5m)
+func WithTolerance(d time.Duration) Option {
+	return func(h *Handler) {
+		h.tolerance = d
+	}
+}
+
+// Handler is an http.Handler that verifies webhook signatures and dispatches events
+type Handler struct {
+	secret    []byte
+	fn        HandlerFunc
+	tolerance time.Duration
+	now       func() time.Time
+}
+
+// NewHandler creates a Handler that verifies deliveries with secret and passes them to fn.
+// It panics if secret is empty, since anyone could then sign deliveries.
+func NewHandler(secret string, fn HandlerFunc, opts ...Option) *Handler {
+	if len(secret) == 0 {
+		panic("webhook: empty secret")
+	}
+	h := &Handler{
+		secret:    []byte(secret),
+		fn:        fn,
+		tolerance: DefaultTolerance,
+		now:       time.Now,
+	}
+
+	for _, opt := range opts {
+		opt(h)
+	}
+
+	return h
+}
+
+// ServeHTTP implements http.Handler
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.Header().Set("Allow", http.MethodPost)
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	body, err := io.ReadAll(io.LimitReader(r.Body, maxBodySize))
+	if err != nil {
+		http.Error(w, "read body", http.StatusBadRequest)
+		return
+	}
+
+	timestamp := r.Header.Get(TimestampHeader)
+	if err := h.verify(body, timestamp, r.Header.Get(SignatureHeader)); err != nil {
+		http.Error(w, err.Error(), http.StatusUnauthorized)
+		return
+	}
+
+	var event scrapeapi.ScrapeResponse
+	if err := json.Unmarshal(body, &event); err != nil {
+		http.Error(w, "decode event", http.StatusBadRequest)
+		return
+	}
+
+	if err := h.fn(r.Context(), &event); err != nil {
+		http.Error(w, "handler failed", http.StatusInternalServerError)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *Handler) verify(body []byte, timestamp, signature string) error {
+	if err := Verify(h.secret, body, timestamp, signature); err != nil {
+		return err
+	}
+
+	if h.tolerance <= 0 {
+		return nil
+	}
+	secs, _ := strconv.ParseInt(timestamp, 10, 64) // already validated by Verify
+	if d := h.now().Sub(time.Unix(secs, 0)); d > h.tolerance || d < -h.tolerance {
+		return ErrExpired
+	}
+	return nil
+}
+
+// Verify checks that signature is a valid signature of body sent at timestamp.
+// It does not check the timestamp's age; Handler does that.
+func Verify(secret, body []byte, timestamp, signature string) error {
+	if timestamp == "" || signature == "" {
+		return ErrMissingSignature
+	}
+	if _, err := strconv.ParseInt(timestamp, 10, 64); err != nil {
+		return ErrInvalidTimestamp
+	}
+
+	got, err := hex.DecodeString(strings.TrimPrefix(signature, signaturePrefix))
+	if err != nil {
+		return ErrInvalidSignature
+	}
+	if !hmac.Equal(got, mac(secret, timestamp, body)) {
+		return ErrInvalidSignature
+	}
+	return nil
+}
+
+// Sign returns the SignatureHeader value for body sent at timestamp (Unix seconds).
+// It is useful for testing webhook receivers.
+func Sign(secret, body []byte, timestamp string) string {
+	return signaturePrefix + hex.EncodeToString(mac(secret, timestamp, body))
+}
+
+func mac(secret []byte, timestamp string, body []byte) []byte {
+	m := hmac.New(sha256.New, secret)
+	m.Write([]byte(timestamp))
+	m.Write([]byte("."))
+	m.Write(body)
+	return m.Sum(nil)
+}
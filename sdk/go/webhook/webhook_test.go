@@ -0,0 +1,112 @@
+package webhook
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	scrapeapi "github.com/dir01/scrapeapi/sdk/go"
+)
+
+const testSecret = "whsec_test"
+
+func TestVerify(t *testing.T) {
+	body := []byte(`{"request_id":"req_1","status":"completed"}`)
+	const ts = "1700000000"
+	valid := Sign([]byte(testSecret), body, ts)
+
+	tests := []struct {
+		name      string
+		secret    string
+		body      []byte
+		timestamp string
+		signature string
+		want      error
+	}{
+		{"valid", testSecret, body, ts, valid, nil},
+		{"valid without prefix", testSecret, body, ts, strings.TrimPrefix(valid, signaturePrefix), nil},
+		{"missing signature", testSecret, body, ts, "", ErrMissingSignature},
+		{"missing timestamp", testSecret, body, "", valid, ErrMissingSignature},
+		{"non-numeric timestamp", testSecret, body, "yesterday", valid, ErrInvalidTimestamp},
+		{"not hex", testSecret, body, ts, "sha256=not-hex", ErrInvalidSignature},
+		{"tampered body", testSecret, []byte(`{"request_id":"req_2","status":"completed"}`), ts, valid, ErrInvalidSignature},
+		{"other timestamp", testSecret, body, "1700000001", valid, ErrInvalidSignature},
+		{"wrong secret", "whsec_other", body, ts, valid, ErrInvalidSignature},
+		{"truncated signature", testSecret, body, ts, valid[:len(valid)-2], ErrInvalidSignature},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := Verify([]byte(tt.secret), tt.body, tt.timestamp, tt.signature)
+			if !errors.Is(err, tt.want) {
+				t.Errorf("Verify = %v, want %v", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestHandler(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+	body := `{"request_id":"req_1","status":"completed"}`
+
+	tests := []struct {
+		name      string
+		method    string
+		body      string
+		age       time.Duration
+		tolerance time.Duration
+		signed    bool
+		fnErr     error
+		want      int
+	}{
+		{"delivered", http.MethodPost, body, 0, DefaultTolerance, true, nil, http.StatusNoContent},
+		{"within tolerance", http.MethodPost, body, 4 * time.Minute, DefaultTolerance, true, nil, http.StatusNoContent},
+		{"clock skew within tolerance", http.MethodPost, body, -4 * time.Minute, DefaultTolerance, true, nil, http.StatusNoContent},
+		{"stale", http.MethodPost, body, 6 * time.Minute, DefaultTolerance, true, nil, http.StatusUnauthorized},
+		{"from the future", http.MethodPost, body, -6 * time.Minute, DefaultTolerance, true, nil, http.StatusUnauthorized},
+		{"tolerance disabled", http.MethodPost, body, 24 * time.Hour, 0, true, nil, http.StatusNoContent},
+		{"unsigned", http.MethodPost, body, 0, DefaultTolerance, false, nil, http.StatusUnauthorized},
+		{"wrong method", http.MethodGet, body, 0, DefaultTolerance, true, nil, http.StatusMethodNotAllowed},
+		{"malformed event", http.MethodPost, `{"request_id":`, 0, DefaultTolerance, true, nil, http.StatusBadRequest},
+		{"handler failed", http.MethodPost, body, 0, DefaultTolerance, true, errors.New("db down"), http.StatusInternalServerError},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got *scrapeapi.ScrapeResponse
+			h := NewHandler(testSecret, func(ctx context.Context, event *scrapeapi.ScrapeResponse) error {
+				got = event
+				return tt.fnErr
+			}, WithTolerance(tt.tolerance))
+			h.now = func() time.Time { return now }
+
+			req := httptest.NewRequest(tt.method, "/hooks", strings.NewReader(tt.body))
+			if tt.signed {
+				ts := strconv.FormatInt(now.Add(-tt.age).Unix(), 10)
+				req.Header.Set(TimestampHeader, ts)
+				req.Header.Set(SignatureHeader, Sign([]byte(testSecret), []byte(tt.body), ts))
+			}
+			rec := httptest.NewRecorder()
+			h.ServeHTTP(rec, req)
+
+			if rec.Code != tt.want {
+				t.Fatalf("status = %d, want %d (%s)", rec.Code, tt.want, rec.Body.String())
+			}
+			if tt.want == http.StatusNoContent && (got == nil || got.RequestID != "req_1") {
+				t.Errorf("handler got event %+v, want req_1", got)
+			}
+		})
+	}
+}
+
+func TestNewHandlerEmptySecret(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Error("NewHandler with an empty secret did not panic")
+		}
+	}()
+	NewHandler("", func(ctx context.Context, event *scrapeapi.ScrapeResponse) error { return nil })
+}